@@ -0,0 +1,298 @@
+// Package cachestatus implements the Cache-Status field defined in RFC 9211 The Cache-Status HTTP Response Header Field.
+package cachestatus
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shogo82148/go-sfv"
+	"github.com/shogo82148/go-sfv/internal/fieldutil"
+)
+
+// FieldName is the name of the Cache-Status field.
+const FieldName = "Cache-Status"
+
+// FwdReason is the reason why the request went forward, defined in RFC 9211 Section 2.2.
+type FwdReason string
+
+const (
+	// FwdBypass means the cache was configured to not handle this request.
+	FwdBypass FwdReason = "bypass"
+
+	// FwdMethod means the request method's semantics require the request to be forwarded.
+	FwdMethod FwdReason = "method"
+
+	// FwdURIMiss means the cache did not contain any responses that matched the request's target URI.
+	FwdURIMiss FwdReason = "uri-miss"
+
+	// FwdVaryMiss means the cache contained a response that matched the target URI,
+	// but its Vary header field prevented this request from using it.
+	FwdVaryMiss FwdReason = "vary-miss"
+
+	// FwdMiss means the cache did not contain any responses that could be used to satisfy this request.
+	FwdMiss FwdReason = "miss"
+
+	// FwdRequest means the cache was able to select a fresh response for the request,
+	// but the request's semantics forced it to be forwarded.
+	FwdRequest FwdReason = "request"
+
+	// FwdStale means the cache was able to select a response for the request, but it was stale.
+	FwdStale FwdReason = "stale"
+
+	// FwdPartial means the cache was able to select a partial response for the request,
+	// but it did not contain all of the requested ranges.
+	FwdPartial FwdReason = "partial"
+)
+
+// Valid reports whether r is one of the reasons defined in RFC 9211.
+func (r FwdReason) Valid() bool {
+	switch r {
+	case FwdBypass, FwdMethod, FwdURIMiss, FwdVaryMiss, FwdMiss, FwdRequest, FwdStale, FwdPartial:
+		return true
+	}
+	return false
+}
+
+// Entry is a member of the Cache-Status field, that describes how a cache handled the request.
+type Entry struct {
+	// Cache identifies the cache.
+	// It is serialized as a Token if possible, otherwise as a String.
+	Cache string
+
+	// Hit is the hit parameter.
+	// It means that the request was satisfied by the cache.
+	Hit bool
+
+	// Fwd is the fwd parameter, or empty if it is omitted.
+	// It means that the request went forward towards the origin.
+	// Parsing keeps unknown reasons, but Validate rejects them.
+	Fwd FwdReason
+
+	// FwdStatus is the fwd-status parameter, or 0 if it is omitted.
+	FwdStatus int
+
+	// TTL is the ttl parameter in seconds, or nil if it is omitted.
+	// It might be negative if the response is stale.
+	TTL *int64
+
+	// Stored is the stored parameter.
+	// It means that the cache stored the response forwarded.
+	Stored bool
+
+	// Collapsed is the collapsed parameter.
+	// It means that the request was collapsed with another request.
+	Collapsed bool
+
+	// Key is the key parameter, or empty if it is omitted.
+	Key string
+
+	// Detail is the detail parameter, or empty if it is omitted.
+	// It is serialized as a Token if possible, otherwise as a String.
+	Detail string
+
+	// Extra is the parameters that are not defined in RFC 9211.
+	Extra sfv.Parameters
+}
+
+// CacheStatus is the list of the entries, in the order of the caches from the origin server to the user agent.
+type CacheStatus []Entry
+
+// Parse parses the Cache-Status fields.
+func Parse(fields []string) (CacheStatus, error) {
+	list, err := sfv.DecodeList(fields)
+	if err != nil {
+		return nil, err
+	}
+	return ParseList(list)
+}
+
+// ParseList converts list into the entries.
+func ParseList(list sfv.List) (CacheStatus, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+	cs := make(CacheStatus, 0, len(list))
+	for _, item := range list {
+		e, err := ParseItem(item)
+		if err != nil {
+			return nil, err
+		}
+		cs = append(cs, e)
+	}
+	return cs, nil
+}
+
+// ParseItem converts item into the entry.
+// The known parameters with invalid values are ignored,
+// so that one malformed member doesn't discard the others received from upstream.
+func ParseItem(item sfv.Item) (Entry, error) {
+	var e Entry
+	var ok bool
+	if e.Cache, ok = fieldutil.StringOf(item.Value); !ok {
+		return Entry{}, fmt.Errorf("cachestatus: the cache identifier must be a token or a string, got %T", item.Value)
+	}
+
+	for _, param := range item.Parameters {
+		prev := e
+		var ok bool
+		switch param.Key {
+		case "hit":
+			e.Hit, ok = param.Value.(bool)
+		case "fwd":
+			var v sfv.Token
+			v, ok = param.Value.(sfv.Token)
+			e.Fwd = FwdReason(v)
+		case "fwd-status":
+			var v int64
+			v, ok = param.Value.(int64)
+			e.FwdStatus = int(v)
+			ok = ok && v >= 100 && v <= 999
+		case "ttl":
+			var v int64
+			v, ok = param.Value.(int64)
+			e.TTL = &v
+		case "stored":
+			e.Stored, ok = param.Value.(bool)
+		case "collapsed":
+			e.Collapsed, ok = param.Value.(bool)
+		case "key":
+			e.Key, ok = param.Value.(string)
+		case "detail":
+			e.Detail, ok = fieldutil.StringOf(param.Value)
+		default:
+			e.Extra = append(e.Extra, param)
+			ok = true
+		}
+		if !ok {
+			// revert the partial assignment of the invalid parameter.
+			e = prev
+		}
+	}
+	return e, nil
+}
+
+// Validate checks that e can be serialized as a member of the Cache-Status field.
+func (e Entry) Validate() error {
+	if e.Cache == "" {
+		return errors.New("cachestatus: the cache identifier is empty")
+	}
+	if e.Hit && e.Fwd != "" {
+		return fmt.Errorf("cachestatus: %q has both hit and fwd", e.Cache)
+	}
+	if e.Fwd != "" && !e.Fwd.Valid() {
+		return fmt.Errorf("cachestatus: %q has invalid fwd reason %q", e.Cache, e.Fwd)
+	}
+	if e.FwdStatus != 0 && (e.FwdStatus < 100 || e.FwdStatus > 999) {
+		return fmt.Errorf("cachestatus: %q has invalid fwd-status %d", e.Cache, e.FwdStatus)
+	}
+	if key, ok := fieldutil.ReservedParam(e.Extra, "hit", "fwd", "fwd-status", "ttl", "stored", "collapsed", "key", "detail"); ok {
+		return fmt.Errorf("cachestatus: %q has the parameter %q in Extra", e.Cache, key)
+	}
+	return nil
+}
+
+// Item converts e into the member of the Cache-Status field.
+func (e Entry) Item() (sfv.Item, error) {
+	if err := e.Validate(); err != nil {
+		return sfv.Item{}, err
+	}
+
+	item := sfv.Item{
+		Value: fieldutil.TokenOrString(e.Cache),
+	}
+	add := func(key string, value sfv.Value) {
+		item.Parameters = append(item.Parameters, sfv.Parameter{Key: key, Value: value})
+	}
+	if e.Hit {
+		add("hit", true)
+	}
+	if e.Fwd != "" {
+		add("fwd", sfv.Token(e.Fwd))
+	}
+	if e.FwdStatus != 0 {
+		add("fwd-status", int64(e.FwdStatus))
+	}
+	if e.TTL != nil {
+		add("ttl", *e.TTL)
+	}
+	if e.Stored {
+		add("stored", true)
+	}
+	if e.Collapsed {
+		add("collapsed", true)
+	}
+	if e.Key != "" {
+		add("key", e.Key)
+	}
+	if e.Detail != "" {
+		add("detail", fieldutil.TokenOrString(e.Detail))
+	}
+	item.Parameters = append(item.Parameters, e.Extra...)
+
+	if err := sfv.ValidateItem(item); err != nil {
+		return sfv.Item{}, err
+	}
+	return item, nil
+}
+
+// List converts cs into the Cache-Status field.
+func (cs CacheStatus) List() (sfv.List, error) {
+	list := make(sfv.List, 0, len(cs))
+	for _, e := range cs {
+		item, err := e.Item()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, item)
+	}
+	return list, nil
+}
+
+// Encode serializes cs.
+func (cs CacheStatus) Encode() (string, error) {
+	list, err := cs.List()
+	if err != nil {
+		return "", err
+	}
+	return sfv.EncodeList(list)
+}
+
+// Lookup returns the first entry of the cache.
+func (cs CacheStatus) Lookup(cache string) (Entry, bool) {
+	for _, e := range cs {
+		if e.Cache == cache {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// LookupAll returns all the entries of the cache, e.g. when the request passed through it more than once.
+func (cs CacheStatus) LookupAll(cache string) CacheStatus {
+	var ret CacheStatus
+	for _, e := range cs {
+		if e.Cache == cache {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+// Append appends e to the received Cache-Status fields, and returns the new field value.
+// The received members are kept byte-for-byte identical.
+func Append(fields []string, e Entry) (string, error) {
+	item, err := e.Item()
+	if err != nil {
+		return "", err
+	}
+	return fieldutil.AppendListMember(fields, item)
+}
+
+// AppendHeader appends e to the Cache-Status field in h.
+func AppendHeader(h sfv.Header, e Entry) error {
+	item, err := e.Item()
+	if err != nil {
+		return err
+	}
+	return fieldutil.AppendHeader(h, FieldName, item)
+}
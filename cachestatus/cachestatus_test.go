@@ -0,0 +1,213 @@
+package cachestatus
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/shogo82148/go-sfv"
+)
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func TestParse(t *testing.T) {
+	// the examples in RFC 9211 Section 3.
+	tests := []struct {
+		field string
+		want  CacheStatus
+	}{
+		{
+			`ExampleCache; hit`,
+			CacheStatus{{Cache: "ExampleCache", Hit: true}},
+		},
+		{
+			`ExampleCache; fwd=uri-miss; stored`,
+			CacheStatus{{Cache: "ExampleCache", Fwd: FwdURIMiss, Stored: true}},
+		},
+		{
+			`ExampleCache; fwd=vary-miss; fwd-status=304`,
+			CacheStatus{{Cache: "ExampleCache", Fwd: FwdVaryMiss, FwdStatus: 304}},
+		},
+		{
+			`ExampleCache; fwd=stale; fwd-status=304`,
+			CacheStatus{{Cache: "ExampleCache", Fwd: FwdStale, FwdStatus: 304}},
+		},
+		{
+			`ExampleCache; hit; ttl=-412`,
+			CacheStatus{{Cache: "ExampleCache", Hit: true, TTL: int64Ptr(-412)}},
+		},
+		{
+			`ExampleCache; fwd=bypass`,
+			CacheStatus{{Cache: "ExampleCache", Fwd: FwdBypass}},
+		},
+		{
+			`OriginCache; hit; ttl=1100, "CDN Company Here"; hit; ttl=545`,
+			CacheStatus{
+				{Cache: "OriginCache", Hit: true, TTL: int64Ptr(1100)},
+				{Cache: "CDN Company Here", Hit: true, TTL: int64Ptr(545)},
+			},
+		},
+		{
+			`ReverseProxyCache; hit, ForwardProxyCache; fwd=uri-miss; collapsed; stored`,
+			CacheStatus{
+				{Cache: "ReverseProxyCache", Hit: true},
+				{Cache: "ForwardProxyCache", Fwd: FwdURIMiss, Collapsed: true, Stored: true},
+			},
+		},
+		{
+			`Cache; fwd=miss; key="/foo"; detail=xyz; x-ext=1`,
+			CacheStatus{
+				{Cache: "Cache", Fwd: FwdMiss, Key: "/foo", Detail: "xyz", Extra: sfv.Parameters{{Key: "x-ext", Value: int64(1)}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		got, err := Parse([]string{tt.field})
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.field, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: want %#v, got %#v", tt.field, tt.want, got)
+		}
+	}
+}
+
+func TestParse_invalid(t *testing.T) {
+	for _, field := range []string{
+		`ExampleCache; hit,`,
+		`1; hit`,
+	} {
+		if _, err := Parse([]string{field}); err == nil {
+			t.Errorf("%q: want error, but not", field)
+		}
+	}
+}
+
+func TestParse_invalidParameter(t *testing.T) {
+	const field = `OriginCache; hit=1; fwd="miss"; fwd-status=1; ttl=1.5; key=foo; detail=1, CDN; fwd=uri-miss; ttl="x"; stored`
+	cs, err := Parse([]string{field})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := CacheStatus{
+		{Cache: "OriginCache"},
+		{Cache: "CDN", Fwd: FwdURIMiss, Stored: true},
+	}
+	if !reflect.DeepEqual(cs, want) {
+		t.Errorf("want %#v, got %#v", want, cs)
+	}
+}
+
+func TestCacheStatus_Encode(t *testing.T) {
+	cs := CacheStatus{
+		{Cache: "OriginCache", Hit: true, TTL: int64Ptr(1100)},
+		{Cache: "CDN Company Here", Fwd: FwdStale, FwdStatus: 304, Stored: true, Detail: "some detail"},
+	}
+	got, err := cs.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `OriginCache;hit;ttl=1100, "CDN Company Here";fwd=stale;fwd-status=304;stored;detail="some detail"`
+	if got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	parsed, err := Parse([]string{got})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, cs) {
+		t.Errorf("want %#v, got %#v", cs, parsed)
+	}
+}
+
+func TestEntry_Validate(t *testing.T) {
+	tests := []Entry{
+		{},
+		{Cache: "c", Hit: true, Fwd: FwdMiss},
+		{Cache: "c", Fwd: "unknown"},
+		{Cache: "c", Fwd: FwdMiss, FwdStatus: 42},
+		{Cache: "c", Extra: sfv.Parameters{{Key: "hit", Value: true}}},
+	}
+	for _, e := range tests {
+		if err := e.Validate(); err == nil {
+			t.Errorf("%#v: want error, but not", e)
+		}
+		if _, err := e.Item(); err == nil {
+			t.Errorf("%#v: want error, but not", e)
+		}
+	}
+
+	// invalid extra parameters
+	e := Entry{Cache: "c", Extra: sfv.Parameters{{Key: "INVALID", Value: true}}}
+	if _, err := e.Item(); err == nil {
+		t.Error("want error, but not")
+	}
+}
+
+func TestCacheStatus_Lookup(t *testing.T) {
+	cs, err := Parse([]string{`Proxy; fwd=miss, CDN; hit, Proxy; hit; ttl=10`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, ok := cs.Lookup("Proxy")
+	if !ok || e.Fwd != FwdMiss {
+		t.Errorf("unexpected entry: %#v", e)
+	}
+	e, ok = cs.Lookup("CDN")
+	if !ok || !e.Hit {
+		t.Errorf("unexpected entry: %#v", e)
+	}
+	if _, ok := cs.Lookup("missing"); ok {
+		t.Error("want not found")
+	}
+	if all := cs.LookupAll("Proxy"); len(all) != 2 || *all[1].TTL != 10 {
+		t.Errorf("unexpected entries: %#v", all)
+	}
+}
+
+func TestAppend(t *testing.T) {
+	got, err := Append([]string{`OriginCache; hit;ttl=1100`, `"Mid Cache";fwd=uri-miss`}, Entry{Cache: "CDN", Hit: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `OriginCache; hit;ttl=1100, "Mid Cache";fwd=uri-miss, CDN;hit`
+	if got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	got, err = Append(nil, Entry{Cache: "CDN", Fwd: FwdBypass})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "CDN;fwd=bypass"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	if _, err := Append([]string{"invalid,"}, Entry{Cache: "CDN", Hit: true}); err == nil {
+		t.Error("want error, but not")
+	}
+	if _, err := Append(nil, Entry{Cache: "CDN", Fwd: "unknown"}); err == nil {
+		t.Error("want error, but not")
+	}
+}
+
+func TestAppendHeader(t *testing.T) {
+	h := make(http.Header)
+	h.Add("Cache-Status", "OriginCache; hit")
+	if err := AppendHeader(h, Entry{Cache: "CDN", Hit: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := AppendHeader(h, Entry{Cache: "Proxy", Fwd: FwdMiss, Stored: true}); err != nil {
+		t.Fatal(err)
+	}
+	got := h.Values("Cache-Status")
+	want := []string{"OriginCache; hit, CDN;hit, Proxy;fwd=miss;stored"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
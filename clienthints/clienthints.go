@@ -0,0 +1,158 @@
+// Package clienthints implements the Accept-CH field defined in RFC 8942 HTTP Client Hints,
+// the Critical-CH field defined in Client Hint Reliability, and the User-Agent Client Hints, e.g. Sec-CH-UA.
+package clienthints
+
+import (
+	"fmt"
+	"net/textproto"
+	"strings"
+
+	"github.com/shogo82148/go-sfv"
+)
+
+// The names of the fields.
+const (
+	AcceptCHField   = "Accept-CH"
+	CriticalCHField = "Critical-CH"
+	VaryField       = "Vary"
+)
+
+// Hints is the list of the client hint names, that is the value of the Accept-CH and Critical-CH fields.
+type Hints []string
+
+// ParseHints parses the Accept-CH or Critical-CH fields.
+func ParseHints(fields []string) (Hints, error) {
+	list, err := sfv.DecodeList(fields)
+	if err != nil {
+		return nil, err
+	}
+	return ParseHintsList(list)
+}
+
+// ParseHintsList converts list into the client hint names.
+func ParseHintsList(list sfv.List) (Hints, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+	hints := make(Hints, 0, len(list))
+	for _, item := range list {
+		t, ok := item.Value.(sfv.Token)
+		if !ok {
+			return nil, fmt.Errorf("clienthints: the client hint name must be a token, got %T", item.Value)
+		}
+		hints = append(hints, string(t))
+	}
+	return hints, nil
+}
+
+// Contains reports whether hints contains name.
+// The names are compared case-insensitively.
+func (hints Hints) Contains(name string) bool {
+	for _, hint := range hints {
+		if strings.EqualFold(hint, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// List converts hints into the Accept-CH or Critical-CH field.
+func (hints Hints) List() (sfv.List, error) {
+	list := make(sfv.List, 0, len(hints))
+	for _, hint := range hints {
+		t := sfv.Token(hint)
+		if !t.Valid() {
+			return nil, fmt.Errorf("clienthints: invalid client hint name %q", hint)
+		}
+		list = append(list, sfv.Item{Value: t})
+	}
+	return list, nil
+}
+
+// Encode serializes hints.
+func (hints Hints) Encode() (string, error) {
+	list, err := hints.List()
+	if err != nil {
+		return "", err
+	}
+	return sfv.EncodeList(list)
+}
+
+// Policy is the client hints that a server requests.
+type Policy struct {
+	// Accept is the client hints that the server would like to receive.
+	Accept Hints
+
+	// Critical is the client hints that the server needs to render the response.
+	// They are added to Accept-CH too, because the client retries the request only if
+	// the critical hints are also in Accept-CH.
+	Critical Hints
+}
+
+// SetHeader sets the Accept-CH and Critical-CH fields in h,
+// and adds the client hints to the Vary field, because the response might vary by them.
+// The fields are removed if there are no hints.
+func (p Policy) SetHeader(h sfv.Header) error {
+	accept := make(Hints, 0, len(p.Accept)+len(p.Critical))
+	for _, hint := range p.Accept {
+		if !accept.Contains(hint) {
+			accept = append(accept, hint)
+		}
+	}
+	for _, hint := range p.Critical {
+		if !accept.Contains(hint) {
+			accept = append(accept, hint)
+		}
+	}
+
+	if err := setHints(h, AcceptCHField, accept); err != nil {
+		return err
+	}
+	if err := setHints(h, CriticalCHField, p.Critical); err != nil {
+		return err
+	}
+	AddVary(h, accept...)
+	return nil
+}
+
+func setHints(h sfv.Header, name string, hints Hints) error {
+	if len(hints) == 0 {
+		h.Del(name)
+		return nil
+	}
+	v, err := hints.Encode()
+	if err != nil {
+		return err
+	}
+	h.Set(name, v)
+	return nil
+}
+
+// AddVary adds the field names to the Vary field in h, unless they are already listed.
+func AddVary(h sfv.Header, names ...string) {
+	var vary []string
+	for _, v := range h.Values(VaryField) {
+		for _, name := range strings.Split(v, ",") {
+			name = textproto.TrimString(name)
+			if name == "*" {
+				// the response varies by everything.
+				return
+			}
+			if name != "" {
+				vary = append(vary, name)
+			}
+		}
+	}
+
+	added := false
+	for _, name := range names {
+		if !Hints(vary).Contains(name) {
+			vary = append(vary, name)
+			added = true
+		}
+	}
+	if !added {
+		return
+	}
+	h.Set(VaryField, strings.Join(vary, ", "))
+}
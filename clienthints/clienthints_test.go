@@ -0,0 +1,91 @@
+package clienthints
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestParseHints(t *testing.T) {
+	hints, err := ParseHints([]string{"Sec-CH-UA-Platform-Version, Sec-CH-UA-Model", "sec-ch-ua-arch"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Hints{UAPlatformVersion, UAModel, "sec-ch-ua-arch"}
+	if !reflect.DeepEqual(hints, want) {
+		t.Errorf("want %#v, got %#v", want, hints)
+	}
+	if !hints.Contains(UAArch) {
+		t.Errorf("want %s to be contained", UAArch)
+	}
+	if hints.Contains(UA) {
+		t.Errorf("want %s not to be contained", UA)
+	}
+
+	got, err := hints.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Sec-CH-UA-Platform-Version, Sec-CH-UA-Model, sec-ch-ua-arch"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	for _, field := range []string{`"Sec-CH-UA-Model"`, `Sec-CH-UA-Model,`, `(Sec-CH-UA-Model)`} {
+		if _, err := ParseHints([]string{field}); err == nil {
+			t.Errorf("%q: want error, but not", field)
+		}
+	}
+	if _, err := (Hints{"Sec CH"}).Encode(); err == nil {
+		t.Error("want error, but not")
+	}
+}
+
+func TestPolicy_SetHeader(t *testing.T) {
+	h := make(http.Header)
+	h.Add("Vary", "Accept-Encoding")
+	h.Add("Vary", "sec-ch-ua-model, Cookie")
+
+	p := Policy{
+		Accept:   Hints{UAModel, UAPlatformVersion},
+		Critical: Hints{UAPlatformVersion, UAFullVersionList},
+	}
+	if err := p.SetHeader(h); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := h.Get(AcceptCHField), "Sec-CH-UA-Model, Sec-CH-UA-Platform-Version, Sec-CH-UA-Full-Version-List"; got != want {
+		t.Errorf("Accept-CH: want %q, got %q", want, got)
+	}
+	if got, want := h.Get(CriticalCHField), "Sec-CH-UA-Platform-Version, Sec-CH-UA-Full-Version-List"; got != want {
+		t.Errorf("Critical-CH: want %q, got %q", want, got)
+	}
+	if got, want := h.Values(VaryField), []string{"Accept-Encoding, sec-ch-ua-model, Cookie, Sec-CH-UA-Platform-Version, Sec-CH-UA-Full-Version-List"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Vary: want %q, got %q", want, got)
+	}
+
+	// remove the fields
+	if err := (Policy{}).SetHeader(h); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := h[AcceptCHField]; ok {
+		t.Error("want Accept-CH to be removed")
+	}
+	if _, ok := h[CriticalCHField]; ok {
+		t.Error("want Critical-CH to be removed")
+	}
+}
+
+func TestAddVary(t *testing.T) {
+	h := make(http.Header)
+	AddVary(h, UA, UAMobile, "sec-ch-ua")
+	if got, want := h.Get(VaryField), "Sec-CH-UA, Sec-CH-UA-Mobile"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	h = make(http.Header)
+	h.Set(VaryField, "*")
+	AddVary(h, UA)
+	if got, want := h.Get(VaryField), "*"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
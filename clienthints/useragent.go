@@ -0,0 +1,254 @@
+package clienthints
+
+import (
+	"fmt"
+
+	"github.com/shogo82148/go-sfv"
+)
+
+// The names of the User-Agent Client Hints.
+const (
+	UA                = "Sec-CH-UA"
+	UAArch            = "Sec-CH-UA-Arch"
+	UABitness         = "Sec-CH-UA-Bitness"
+	UAFormFactors     = "Sec-CH-UA-Form-Factors"
+	UAFullVersion     = "Sec-CH-UA-Full-Version"
+	UAFullVersionList = "Sec-CH-UA-Full-Version-List"
+	UAMobile          = "Sec-CH-UA-Mobile"
+	UAModel           = "Sec-CH-UA-Model"
+	UAPlatform        = "Sec-CH-UA-Platform"
+	UAPlatformVersion = "Sec-CH-UA-Platform-Version"
+	UAWoW64           = "Sec-CH-UA-WoW64"
+)
+
+// Brand is a member of the Sec-CH-UA and Sec-CH-UA-Full-Version-List fields.
+type Brand struct {
+	// Brand is the brand name, e.g. "Chromium".
+	Brand string
+
+	// Version is the v parameter, that is the significant version in Sec-CH-UA,
+	// or the full version in Sec-CH-UA-Full-Version-List.
+	Version string
+}
+
+// Brands is the Sec-CH-UA or Sec-CH-UA-Full-Version-List field.
+type Brands []Brand
+
+// ParseBrands parses the Sec-CH-UA or Sec-CH-UA-Full-Version-List fields.
+func ParseBrands(fields []string) (Brands, error) {
+	list, err := sfv.DecodeList(fields)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBrandsList(list)
+}
+
+// ParseBrandsList converts list into the brands.
+// The parameters other than v are ignored.
+func ParseBrandsList(list sfv.List) (Brands, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+	brands := make(Brands, 0, len(list))
+	for _, item := range list {
+		name, ok := item.Value.(string)
+		if !ok {
+			return nil, fmt.Errorf("clienthints: the brand must be a string, got %T", item.Value)
+		}
+		b := Brand{Brand: name}
+		if v := item.Parameters.Get("v"); v != nil {
+			if b.Version, ok = v.(string); !ok {
+				return nil, fmt.Errorf("clienthints: the version of %q must be a string, got %T", name, v)
+			}
+		}
+		brands = append(brands, b)
+	}
+	return brands, nil
+}
+
+// Lookup returns the brand of the name.
+func (brands Brands) Lookup(name string) (Brand, bool) {
+	for _, b := range brands {
+		if b.Brand == name {
+			return b, true
+		}
+	}
+	return Brand{}, false
+}
+
+// List converts brands into the Sec-CH-UA or Sec-CH-UA-Full-Version-List field.
+// The v parameter is omitted if Version is empty.
+func (brands Brands) List() (sfv.List, error) {
+	list := make(sfv.List, 0, len(brands))
+	for _, b := range brands {
+		item := sfv.Item{
+			Value: b.Brand,
+		}
+		if b.Version != "" {
+			item.Parameters = sfv.Parameters{{Key: "v", Value: b.Version}}
+		}
+		if err := sfv.ValidateItem(item); err != nil {
+			return nil, err
+		}
+		list = append(list, item)
+	}
+	return list, nil
+}
+
+// Encode serializes brands.
+func (brands Brands) Encode() (string, error) {
+	list, err := brands.List()
+	if err != nil {
+		return "", err
+	}
+	return sfv.EncodeList(list)
+}
+
+// UserAgent is the User-Agent Client Hints.
+// The fields are the zero values if the corresponding hints are not sent.
+type UserAgent struct {
+	// Brands is the Sec-CH-UA field.
+	Brands Brands
+
+	// FullVersionList is the Sec-CH-UA-Full-Version-List field.
+	FullVersionList Brands
+
+	// FullVersion is the deprecated Sec-CH-UA-Full-Version field.
+	FullVersion string
+
+	// Mobile is the Sec-CH-UA-Mobile field.
+	Mobile bool
+
+	// Platform is the Sec-CH-UA-Platform field, e.g. "Windows".
+	Platform string
+
+	// PlatformVersion is the Sec-CH-UA-Platform-Version field.
+	PlatformVersion string
+
+	// Arch is the Sec-CH-UA-Arch field, e.g. "x86".
+	Arch string
+
+	// Bitness is the Sec-CH-UA-Bitness field, e.g. "64".
+	Bitness string
+
+	// Model is the Sec-CH-UA-Model field.
+	Model string
+
+	// WoW64 is the Sec-CH-UA-WoW64 field.
+	WoW64 bool
+
+	// FormFactors is the Sec-CH-UA-Form-Factors field, e.g. "Desktop".
+	FormFactors []string
+}
+
+// ParseUserAgent parses the User-Agent Client Hints in h.
+// Each hint is parsed separately, and the invalid hints are left as the zero values.
+// If some hints are invalid, it returns the other hints and the error of the first invalid one,
+// so callers can use the result regardless of the error.
+func ParseUserAgent(h sfv.Header) (UserAgent, error) {
+	var ua UserAgent
+	var first error
+	check := func(err error) {
+		if first == nil {
+			first = err
+		}
+	}
+
+	var err error
+	ua.Brands, err = parseBrandsField(h, UA)
+	check(err)
+	ua.FullVersionList, err = parseBrandsField(h, UAFullVersionList)
+	check(err)
+	ua.Mobile, err = parseBoolean(h, UAMobile)
+	check(err)
+	ua.WoW64, err = parseBoolean(h, UAWoW64)
+	check(err)
+
+	strs := []struct {
+		name string
+		v    *string
+	}{
+		{UAFullVersion, &ua.FullVersion},
+		{UAPlatform, &ua.Platform},
+		{UAPlatformVersion, &ua.PlatformVersion},
+		{UAArch, &ua.Arch},
+		{UABitness, &ua.Bitness},
+		{UAModel, &ua.Model},
+	}
+	for _, s := range strs {
+		*s.v, err = parseString(h, s.name)
+		check(err)
+	}
+
+	ua.FormFactors, err = parseFormFactors(h)
+	check(err)
+	return ua, first
+}
+
+func parseFormFactors(h sfv.Header) ([]string, error) {
+	fields := h.Values(UAFormFactors)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	list, err := sfv.DecodeList(fields)
+	if err != nil {
+		return nil, fmt.Errorf("clienthints: failed to parse %s: %w", UAFormFactors, err)
+	}
+	var ret []string
+	for _, item := range list {
+		s, ok := item.Value.(string)
+		if !ok {
+			return nil, fmt.Errorf("clienthints: the form factor must be a string, got %T", item.Value)
+		}
+		ret = append(ret, s)
+	}
+	return ret, nil
+}
+
+func parseBrandsField(h sfv.Header, name string) (Brands, error) {
+	fields := h.Values(name)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	brands, err := ParseBrands(fields)
+	if err != nil {
+		return nil, fmt.Errorf("clienthints: failed to parse %s: %w", name, err)
+	}
+	return brands, nil
+}
+
+func parseItem(h sfv.Header, name string) (sfv.Value, error) {
+	fields := h.Values(name)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	item, err := sfv.DecodeItem(fields)
+	if err != nil {
+		return nil, fmt.Errorf("clienthints: failed to parse %s: %w", name, err)
+	}
+	return item.Value, nil
+}
+
+func parseBoolean(h sfv.Header, name string) (bool, error) {
+	v, err := parseItem(h, name)
+	if err != nil || v == nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("clienthints: %s must be a boolean, got %T", name, v)
+	}
+	return b, nil
+}
+
+func parseString(h sfv.Header, name string) (string, error) {
+	v, err := parseItem(h, name)
+	if err != nil || v == nil {
+		return "", err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("clienthints: %s must be a string, got %T", name, v)
+	}
+	return s, nil
+}
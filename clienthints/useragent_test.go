@@ -0,0 +1,131 @@
+package clienthints
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestParseUserAgent(t *testing.T) {
+	h := make(http.Header)
+	h.Set(UA, `"Chromium";v="124", "Google Chrome";v="124", "Not-A.Brand";v="99"`)
+	h.Set(UAFullVersionList, `"Chromium";v="124.0.6367.91", "Google Chrome";v="124.0.6367.91", "Not-A.Brand";v="99.0.0.0"`)
+	h.Set(UAMobile, `?0`)
+	h.Set(UAPlatform, `"Windows"`)
+	h.Set(UAPlatformVersion, `"15.0.0"`)
+	h.Set(UAArch, `"x86"`)
+	h.Set(UABitness, `"64"`)
+	h.Set(UAModel, `""`)
+	h.Set(UAWoW64, `?1`)
+	h.Set(UAFormFactors, `"Desktop", "XR"`)
+
+	ua, err := ParseUserAgent(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := UserAgent{
+		Brands: Brands{
+			{Brand: "Chromium", Version: "124"},
+			{Brand: "Google Chrome", Version: "124"},
+			{Brand: "Not-A.Brand", Version: "99"},
+		},
+		FullVersionList: Brands{
+			{Brand: "Chromium", Version: "124.0.6367.91"},
+			{Brand: "Google Chrome", Version: "124.0.6367.91"},
+			{Brand: "Not-A.Brand", Version: "99.0.0.0"},
+		},
+		Mobile:          false,
+		Platform:        "Windows",
+		PlatformVersion: "15.0.0",
+		Arch:            "x86",
+		Bitness:         "64",
+		Model:           "",
+		WoW64:           true,
+		FormFactors:     []string{"Desktop", "XR"},
+	}
+	if !reflect.DeepEqual(ua, want) {
+		t.Errorf("want %#v, got %#v", want, ua)
+	}
+
+	if b, ok := ua.Brands.Lookup("Google Chrome"); !ok || b.Version != "124" {
+		t.Errorf("unexpected brand: %#v", b)
+	}
+	if _, ok := ua.Brands.Lookup("Firefox"); ok {
+		t.Error("want not found, but found")
+	}
+
+	got, err := ua.Brands.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := h.Get(UA); got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestParseUserAgent_empty(t *testing.T) {
+	ua, err := ParseUserAgent(make(http.Header))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ua, UserAgent{}) {
+		t.Errorf("want the zero value, got %#v", ua)
+	}
+}
+
+func TestParseUserAgent_invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{UA, `Chromium;v="124"`},
+		{UA, `"Chromium";v=124`},
+		{UA, `"Chromium";v="124",`},
+		{UAFullVersionList, `("Chromium");v="124"`},
+		{UAMobile, `?2`},
+		{UAMobile, `"?0"`},
+		{UAPlatform, `Windows`},
+		{UABitness, `64`},
+		{UAFormFactors, `Desktop`},
+	}
+	for _, tt := range tests {
+		h := make(http.Header)
+		h.Set(tt.name, tt.value)
+		if _, err := ParseUserAgent(h); err == nil {
+			t.Errorf("%s: %s: want error, but not", tt.name, tt.value)
+		}
+	}
+}
+
+func TestParseUserAgent_partiallyInvalid(t *testing.T) {
+	h := make(http.Header)
+	h.Set(UA, `"Chromium";v="124"`)
+	h.Set(UAMobile, `?1`)
+	h.Set(UAPlatform, `"Android"`)
+	h.Set(UAModel, `Pixel`)
+	h.Set(UAFormFactors, `Mobile`)
+
+	ua, err := ParseUserAgent(h)
+	if err == nil {
+		t.Error("want error, but not")
+	}
+	want := UserAgent{
+		Brands:   Brands{{Brand: "Chromium", Version: "124"}},
+		Mobile:   true,
+		Platform: "Android",
+	}
+	if !reflect.DeepEqual(ua, want) {
+		t.Errorf("want %#v, got %#v", want, ua)
+	}
+}
+
+func TestBrands_Encode(t *testing.T) {
+	brands := Brands{{Brand: "Chromium"}, {Brand: "Not-A.Brand", Version: "99"}}
+	got, err := brands.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"Chromium", "Not-A.Brand";v="99"`; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
@@ -255,6 +255,8 @@ type decodeState struct {
 	endOfField bool
 	sepIdx     int
 	buf        bytes.Buffer
+	scratch    []byte
+	opts       *DecodeOptions
 }
 
 func (s *decodeState) peek() int {
@@ -669,6 +671,7 @@ func (s *decodeState) decodeDisplayString() (Value, error) {
 
 func (s *decodeState) decodeParameters() (Parameters, error) {
 	var params Parameters
+	var positions []Pos
 	seenKeys := map[string]int{}
 	for {
 		if s.peek() != ';' {
@@ -677,6 +680,10 @@ func (s *decodeState) decodeParameters() (Parameters, error) {
 		s.next() // skip ';'
 		s.skipSPs()
 
+		var pos Pos
+		if s.opts != nil {
+			pos = s.pos()
+		}
 		key, err := s.decodeKey()
 		if err != nil {
 			return nil, err
@@ -694,6 +701,12 @@ func (s *decodeState) decodeParameters() (Parameters, error) {
 		if i, ok := seenKeys[key]; ok {
 			// parameters already contains a key,
 			// overwrite its value
+			if s.opts != nil {
+				if err := s.duplicateKey(key, pos, positions[i], true); err != nil {
+					return nil, err
+				}
+				positions[i] = pos
+			}
 			params[i] = Parameter{
 				Key:   key,
 				Value: value,
@@ -704,6 +717,9 @@ func (s *decodeState) decodeParameters() (Parameters, error) {
 				Key:   key,
 				Value: value,
 			})
+			if s.opts != nil {
+				positions = append(positions, pos)
+			}
 		}
 	}
 
@@ -734,9 +750,50 @@ func (s *decodeState) decodeKey() (string, error) {
 }
 
 func (s *decodeState) decodeItemOrInnerItem() (Item, error) {
+	v, err := s.decodeBareItemOrInnerList()
+	if err != nil {
+		return Item{}, err
+	}
+	params, err := s.decodeParameters()
+	if err != nil {
+		return Item{}, err
+	}
+
+	return Item{
+		Value:      v,
+		Parameters: params,
+	}, nil
+}
+
+// decodeRawItemOrInnerItem is similar to decodeItemOrInnerItem,
+// but returns the bare item or the inner list as Raw.
+func (s *decodeState) decodeRawItemOrInnerItem() (Item, error) {
+	start := s.pos()
+	if _, err := s.decodeBareItemOrInnerList(); err != nil {
+		return Item{}, err
+	}
+	raw := Raw(s.text(s.span(start)))
+	params, err := s.decodeParameters()
+	if err != nil {
+		return Item{}, err
+	}
+
+	return Item{
+		Value:      raw,
+		Parameters: params,
+	}, nil
+}
+
+// rawMember reports whether the member should be decoded as Raw.
+func (s *decodeState) rawMember(index int, key string) bool {
+	return s.opts != nil && s.opts.RawMembers != nil && s.opts.RawMembers(index, key)
+}
+
+// decodeBareItemOrInnerList parses a bare item or an inner list without its parameters.
+func (s *decodeState) decodeBareItemOrInnerList() (Value, error) {
 	if s.peek() != '(' {
-		// It might be an Item
-		return s.decodeItem()
+		// It might be a bare item
+		return s.decodeBareItem()
 	}
 	s.next() // skip '('
 
@@ -752,23 +809,15 @@ func (s *decodeState) decodeItemOrInnerItem() (Item, error) {
 
 		item, err := s.decodeItem()
 		if err != nil {
-			return Item{}, err
+			return nil, err
 		}
 		list = append(list, item)
 		ch = s.peek()
 		if ch != ' ' && ch != ')' {
-			return Item{}, s.errUnexpectedCharacter()
+			return nil, s.errUnexpectedCharacter()
 		}
 	}
-	params, err := s.decodeParameters()
-	if err != nil {
-		return Item{}, err
-	}
-
-	return Item{
-		Value:      list,
-		Parameters: params,
-	}, nil
+	return list, nil
 }
 
 func (s *decodeState) decodeList() (List, error) {
@@ -780,7 +829,13 @@ func (s *decodeState) decodeList() (List, error) {
 	}
 
 	for {
-		item, err := s.decodeItemOrInnerItem()
+		var item Item
+		var err error
+		if s.rawMember(len(list), "") {
+			item, err = s.decodeRawItemOrInnerItem()
+		} else {
+			item, err = s.decodeItemOrInnerItem()
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -811,19 +866,29 @@ func (s *decodeState) decodeDictionary() (Dictionary, error) {
 	}
 
 	var dict Dictionary
+	var positions []Pos
 	seenKeys := map[string]int{}
-	for {
+	for index := 0; ; index++ {
 		// decode keys
+		var pos Pos
+		if s.opts != nil {
+			pos = s.pos()
+		}
 		key, err := s.decodeKey()
 		if err != nil {
 			return nil, err
 		}
 
 		// decode items
+		raw := s.rawMember(index, key)
 		var item Item
 		if s.peek() == '=' {
 			s.next() // skip '='
-			item, err = s.decodeItemOrInnerItem()
+			if raw {
+				item, err = s.decodeRawItemOrInnerItem()
+			} else {
+				item, err = s.decodeItemOrInnerItem()
+			}
 			if err != nil {
 				return nil, err
 			}
@@ -836,10 +901,19 @@ func (s *decodeState) decodeDictionary() (Dictionary, error) {
 				Value:      true,
 				Parameters: params,
 			}
+			if raw {
+				item.Value = Raw("?1")
+			}
 		}
 		if i, ok := seenKeys[key]; ok {
 			// parameters already contains a key,
 			// overwrite its value
+			if s.opts != nil {
+				if err := s.duplicateKey(key, pos, positions[i], false); err != nil {
+					return nil, err
+				}
+				positions[i] = pos
+			}
 			dict[i] = DictMember{
 				Key:  key,
 				Item: item,
@@ -850,6 +924,9 @@ func (s *decodeState) decodeDictionary() (Dictionary, error) {
 				Key:  key,
 				Item: item,
 			})
+			if s.opts != nil {
+				positions = append(positions, pos)
+			}
 		}
 
 		// skip commas
@@ -871,12 +948,112 @@ func (s *decodeState) decodeDictionary() (Dictionary, error) {
 	return dict, nil
 }
 
-// DecodeList decodes fields as Structured Field Values,
+// DuplicateKey describes a key that appears more than once in a Dictionary or Parameters.
+type DuplicateKey struct {
+	// Key is the duplicated key.
+	Key string
+
+	// Pos is the position of the key that overwrites the previous value.
+	Pos Pos
+
+	// Previous is the position of the key whose value is overwritten.
+	Previous Pos
+
+	// Parameter is true if the key is a key of Parameters,
+	// and false if it is a key of a Dictionary.
+	Parameter bool
+}
+
+// DecodeOptions is options for decoding Structured Field Values.
+// The zero value is the same as DecodeItem, DecodeList and DecodeDictionary.
+type DecodeOptions struct {
+	// OnDuplicateKey is called for each key that overwrites the value of the previous same key.
+	OnDuplicateKey func(dup DuplicateKey)
+
+	// DisallowDuplicateKeys makes the decoder return an error on duplicated keys
+	// instead of overwriting the previous value.
+	// It is useful for security-sensitive fields, where duplicated keys
+	// might be a sign of header injection.
+	DisallowDuplicateKeys bool
+
+	// RawMembers selects the members of Lists and Dictionaries that are decoded as Raw.
+	// It is called with the index of the member and its key (empty for Lists).
+	// The bare item or the inner list of the selected member is kept as Raw,
+	// and its parameters are decoded as usual.
+	// The value of a Dictionary member without "=" is Raw("?1").
+	RawMembers func(index int, key string) bool
+
+	// ValidateSkipped makes LookupDictionaryMember check that
+	// the members other than the requested one are well-formed.
+	// By default, they are skipped without validation.
+	ValidateSkipped bool
+}
+
+// duplicateKey reports the duplicated key.
+func (s *decodeState) duplicateKey(key string, pos, prev Pos, param bool) error {
+	if s.opts.OnDuplicateKey != nil {
+		s.opts.OnDuplicateKey(DuplicateKey{
+			Key:       key,
+			Pos:       pos,
+			Previous:  prev,
+			Parameter: param,
+		})
+	}
+	if s.opts.DisallowDuplicateKeys {
+		return fmt.Errorf("sfv: duplicated key %q at line %d, offset %d", key, pos.Line, pos.Offset)
+	}
+	return nil
+}
+
+// DecodeItem decodes fields as Structured Field Values,
 // and returns the result as an Item.
 func DecodeItem(fields []string) (Item, error) {
-	state := &decodeState{
+	return decodeItem(&decodeState{
 		fields: fields,
-	}
+	})
+}
+
+// DecodeList decodes fields as Structured Field Values,
+// and returns the result as a List.
+func DecodeList(fields []string) (List, error) {
+	return decodeList(&decodeState{
+		fields: fields,
+	})
+}
+
+// DecodeDictionary decodes fields as Structured Field Values,
+// and returns the result as a Dictionary.
+func DecodeDictionary(fields []string) (Dictionary, error) {
+	return decodeDictionary(&decodeState{
+		fields: fields,
+	})
+}
+
+// DecodeItem is similar to the package-level DecodeItem, but uses the options.
+func (opts DecodeOptions) DecodeItem(fields []string) (Item, error) {
+	return decodeItem(&decodeState{
+		fields: fields,
+		opts:   &opts,
+	})
+}
+
+// DecodeList is similar to the package-level DecodeList, but uses the options.
+func (opts DecodeOptions) DecodeList(fields []string) (List, error) {
+	return decodeList(&decodeState{
+		fields: fields,
+		opts:   &opts,
+	})
+}
+
+// DecodeDictionary is similar to the package-level DecodeDictionary, but uses the options.
+func (opts DecodeOptions) DecodeDictionary(fields []string) (Dictionary, error) {
+	return decodeDictionary(&decodeState{
+		fields: fields,
+		opts:   &opts,
+	})
+}
+
+func decodeItem(state *decodeState) (Item, error) {
 	state.skipSPs()
 	ret, err := state.decodeItem()
 	if err != nil {
@@ -889,12 +1066,7 @@ func DecodeItem(fields []string) (Item, error) {
 	return ret, nil
 }
 
-// DecodeList decodes fields as Structured Field Values,
-// and returns the result as a List.
-func DecodeList(fields []string) (List, error) {
-	state := &decodeState{
-		fields: fields,
-	}
+func decodeList(state *decodeState) (List, error) {
 	state.skipSPs()
 	ret, err := state.decodeList()
 	if err != nil {
@@ -907,12 +1079,7 @@ func DecodeList(fields []string) (List, error) {
 	return ret, nil
 }
 
-// DecodeDictionary decodes fields as Structured Field Values,
-// and returns the result as a Dictionary.
-func DecodeDictionary(fields []string) (Dictionary, error) {
-	state := &decodeState{
-		fields: fields,
-	}
+func decodeDictionary(state *decodeState) (Dictionary, error) {
 	state.skipSPs()
 	ret, err := state.decodeDictionary()
 	if err != nil {
@@ -924,3 +1091,51 @@ func DecodeDictionary(fields []string) (Dictionary, error) {
 	}
 	return ret, nil
 }
+
+// ParseBareItem parses s as a bare item according to RFC 9651 Section 4.2.3.1.
+// s must contain exactly one bare item, without leading or trailing spaces.
+func ParseBareItem(s string) (Value, error) {
+	state := &decodeState{
+		fields: []string{s},
+	}
+	v, err := state.decodeBareItem()
+	if err != nil {
+		return nil, err
+	}
+	if state.peek() != endOfInput {
+		return nil, state.errUnexpectedCharacter()
+	}
+	return v, nil
+}
+
+// ParseKey parses s as a key according to RFC 9651 Section 4.2.3.3.
+// s must contain exactly one key, without leading or trailing spaces.
+func ParseKey(s string) (string, error) {
+	state := &decodeState{
+		fields: []string{s},
+	}
+	if err := state.skipKey(); err != nil {
+		return "", err
+	}
+	if state.peek() != endOfInput {
+		return "", state.errUnexpectedCharacter()
+	}
+	return s, nil
+}
+
+// ParseParameters parses s as parameters according to RFC 9651 Section 4.2.3.2.
+// s must start with ';', e.g. ";a=1;b". It returns nil if s is empty.
+// A duplicated key overwrites the previous value.
+func ParseParameters(s string) (Parameters, error) {
+	state := &decodeState{
+		fields: []string{s},
+	}
+	params, err := state.decodeParameters()
+	if err != nil {
+		return nil, err
+	}
+	if state.peek() != endOfInput {
+		return nil, state.errUnexpectedCharacter()
+	}
+	return params, nil
+}
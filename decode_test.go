@@ -2,11 +2,117 @@ package sfv
 
 import (
 	"fmt"
+	"reflect"
 	"runtime"
 	"testing"
 	"time"
 )
 
+func TestDecodeOptions_OnDuplicateKey(t *testing.T) {
+	var got []DuplicateKey
+	opts := DecodeOptions{
+		OnDuplicateKey: func(dup DuplicateKey) {
+			got = append(got, dup)
+		},
+	}
+	dict, err := opts.DecodeDictionary([]string{"a=1;x;x=2, b", "a=3, a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DuplicateKey{
+		{Key: "x", Pos: Pos{0, 6}, Previous: Pos{0, 4}, Parameter: true},
+		{Key: "a", Pos: Pos{1, 0}, Previous: Pos{0, 0}},
+		{Key: "a", Pos: Pos{1, 5}, Previous: Pos{1, 0}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// the values are overwritten in the same way as DecodeDictionary.
+	wantDict, err := DecodeDictionary([]string{"a=1;x;x=2, b", "a=3, a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dict, wantDict) {
+		t.Errorf("want %v, got %v", wantDict, dict)
+	}
+}
+
+func TestDecodeOptions_DisallowDuplicateKeys(t *testing.T) {
+	opts := DecodeOptions{
+		DisallowDuplicateKeys: true,
+	}
+
+	if _, err := opts.DecodeDictionary([]string{"a=1, b=2", "a=3"}); err == nil {
+		t.Error("want error, but not")
+	}
+	if _, err := opts.DecodeList([]string{"a;x=1;x=2"}); err == nil {
+		t.Error("want error, but not")
+	}
+	if _, err := opts.DecodeItem([]string{"a;x=1;y=2;x"}); err == nil {
+		t.Error("want error, but not")
+	}
+	if _, err := opts.DecodeList([]string{"(a;x b;x);x"}); err != nil {
+		t.Errorf("want no error, got %v", err)
+	}
+
+	dict, err := opts.DecodeDictionary([]string{"a=1, b=2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dict.Len() != 2 {
+		t.Errorf("want 2 members, got %d", dict.Len())
+	}
+}
+
+func TestDecodeOptions_RawMembers(t *testing.T) {
+	opts := DecodeOptions{
+		RawMembers: func(index int, key string) bool {
+			return key == "a" || key == "c" || index == 1
+		},
+	}
+
+	dict, err := opts.DecodeDictionary([]string{`a=(1   2.50);x=1, b="foo", c;y`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Dictionary{
+		{Key: "a", Item: Item{Value: Raw("(1   2.50)"), Parameters: Parameters{{Key: "x", Value: int64(1)}}}},
+		{Key: "b", Item: Item{Value: Raw(`"foo"`)}},
+		{Key: "c", Item: Item{Value: Raw("?1"), Parameters: Parameters{{Key: "y", Value: true}}}},
+	}
+	if !reflect.DeepEqual(dict, want) {
+		t.Errorf("want %v, got %v", want, dict)
+	}
+
+	list, err := opts.DecodeList([]string{"1.50, 2.50", "3.50"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantList := List{
+		{Value: 1.5},
+		{Value: Raw("2.50")},
+		{Value: 3.5},
+	}
+	if !reflect.DeepEqual(list, wantList) {
+		t.Errorf("want %v, got %v", wantList, list)
+	}
+
+	// the raw members are still validated.
+	if _, err := opts.DecodeList([]string{"1, (2"}); err == nil {
+		t.Error("want error, but not")
+	}
+
+	// the raw members are written verbatim.
+	val, err := EncodeDictionary(dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `a=(1   2.50);x=1, b="foo", c=?1;y`; val != want {
+		t.Errorf("want %q, got %q", want, val)
+	}
+}
+
 func BenchmarkDecodeInteger(b *testing.B) {
 	v := []string{"-123456789012345"}
 	for i := 0; i < b.N; i++ {
@@ -244,3 +350,81 @@ func BenchmarkDecodeDictionary(b *testing.B) {
 		}
 	}
 }
+
+func TestParseBareItem(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Value
+	}{
+		{"42", int64(42)},
+		{"-1.5", -1.5},
+		{`"foo"`, "foo"},
+		{"foo/bar", Token("foo/bar")},
+		{":AQID:", []byte{1, 2, 3}},
+		{"?0", false},
+		{"@1659578233", time.Unix(1659578233, 0)},
+		{`%"caf%c3%a9"`, DisplayString("café")},
+	}
+	for _, tt := range tests {
+		got, err := ParseBareItem(tt.in)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: want %#v, got %#v", tt.in, tt.want, got)
+		}
+	}
+
+	for _, in := range []string{"", " 1", "1 ", "1;a", "(1)", "1, 2"} {
+		if _, err := ParseBareItem(in); err == nil {
+			t.Errorf("%q: want error, but not", in)
+		}
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	for _, in := range []string{"a", "*", "a_b-c.d*1"} {
+		got, err := ParseKey(in)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", in, err)
+			continue
+		}
+		if got != in {
+			t.Errorf("want %q, got %q", in, got)
+		}
+	}
+	for _, in := range []string{"", "A", "1a", "a b", "a=1"} {
+		if _, err := ParseKey(in); err == nil {
+			t.Errorf("%q: want error, but not", in)
+		}
+	}
+}
+
+func TestParseParameters(t *testing.T) {
+	got, err := ParseParameters(`;a=1; b;a="x"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Parameters{
+		{Key: "a", Value: "x"},
+		{Key: "b", Value: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %#v, got %#v", want, got)
+	}
+
+	got, err = ParseParameters("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Errorf("want nil, got %#v", got)
+	}
+
+	for _, in := range []string{"a=1", ";a=1 ", ";A", ";a=(1)"} {
+		if _, err := ParseParameters(in); err == nil {
+			t.Errorf("%q: want error, but not", in)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+// Package digest implements the Content-Digest, Repr-Digest, Want-Content-Digest and Want-Repr-Digest fields
+// defined in RFC 9530 Digest Fields.
+package digest
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/shogo82148/go-sfv"
+)
+
+// The names of the fields.
+const (
+	ContentDigestField     = "Content-Digest"
+	ReprDigestField        = "Repr-Digest"
+	WantContentDigestField = "Want-Content-Digest"
+	WantReprDigestField    = "Want-Repr-Digest"
+)
+
+var (
+	// ErrMismatch is returned when the data doesn't match the digest.
+	ErrMismatch = errors.New("digest: digest mismatch")
+
+	// ErrNoAvailableAlgorithm is returned when the digests contain no available algorithm.
+	ErrNoAvailableAlgorithm = errors.New("digest: no available algorithm")
+)
+
+// Algorithm is a hashing algorithm key registered in RFC 9530 Section 5.
+type Algorithm string
+
+// The active hashing algorithms registered in RFC 9530 Section 5.
+const (
+	SHA256 Algorithm = "sha-256"
+	SHA512 Algorithm = "sha-512"
+)
+
+// availableAlgorithms is the algorithms that this package can compute, in the order of preference.
+// sha-256 comes first, because it is the most widely supported;
+// Compute uses it by default.
+var availableAlgorithms = []Algorithm{SHA256, SHA512}
+
+// Available reports whether this package can compute the digest of a.
+// The deprecated algorithms, e.g. md5 and sha, are not available.
+func (a Algorithm) Available() bool {
+	return a.new() != nil
+}
+
+// new returns a new hash.Hash of a, or nil if a is not available.
+func (a Algorithm) new() hash.Hash {
+	switch a {
+	case SHA256:
+		return sha256.New()
+	case SHA512:
+		return sha512.New()
+	}
+	return nil
+}
+
+// Digest is a member of the Content-Digest and Repr-Digest fields.
+type Digest struct {
+	Algorithm Algorithm
+	Value     []byte
+}
+
+// Digests is the Content-Digest or Repr-Digest field.
+type Digests []Digest
+
+// Parse parses the Content-Digest or Repr-Digest fields.
+// The parameters of the members are ignored.
+func Parse(fields []string) (Digests, error) {
+	dict, err := sfv.DecodeDictionary(fields)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDictionary(dict)
+}
+
+// ParseDictionary converts dict into the digests.
+func ParseDictionary(dict sfv.Dictionary) (Digests, error) {
+	if len(dict) == 0 {
+		return nil, nil
+	}
+	d := make(Digests, 0, len(dict))
+	for _, member := range dict {
+		v, ok := member.Item.Value.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("digest: the digest of %q must be a byte sequence, got %T", member.Key, member.Item.Value)
+		}
+		d = append(d, Digest{Algorithm: Algorithm(member.Key), Value: v})
+	}
+	return d, nil
+}
+
+// Get returns the digest of the algorithm.
+func (d Digests) Get(alg Algorithm) ([]byte, bool) {
+	for _, v := range d {
+		if v.Algorithm == alg {
+			return v.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Dictionary converts d into the Content-Digest or Repr-Digest field.
+func (d Digests) Dictionary() (sfv.Dictionary, error) {
+	dict := make(sfv.Dictionary, 0, len(d))
+	for _, v := range d {
+		if !sfv.IsValidKey(string(v.Algorithm)) {
+			return nil, fmt.Errorf("digest: invalid algorithm %q", v.Algorithm)
+		}
+		dict = append(dict, sfv.DictMember{Key: string(v.Algorithm), Item: sfv.Item{Value: v.Value}})
+	}
+	return dict, nil
+}
+
+// Encode serializes d.
+func (d Digests) Encode() (string, error) {
+	dict, err := d.Dictionary()
+	if err != nil {
+		return "", err
+	}
+	return sfv.EncodeDictionary(dict)
+}
+
+// Compute reads r until EOF, and returns the digests of the algorithms.
+// If algs is empty, it computes the digest of the most preferred algorithm, that is sha-256.
+func Compute(r io.Reader, algs ...Algorithm) (Digests, error) {
+	if len(algs) == 0 {
+		algs = availableAlgorithms[:1]
+	}
+	dr, err := NewReader(r, algs...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(io.Discard, dr); err != nil {
+		return nil, err
+	}
+	return dr.Digests(), nil
+}
+
+// Verify reads r until EOF, and verifies that the data matches d.
+// The digests of the algorithms that are not available are ignored.
+// It returns ErrNoAvailableAlgorithm if d has no available algorithm,
+// and ErrMismatch if any of the digests doesn't match.
+func (d Digests) Verify(r io.Reader) error {
+	vr, err := NewVerifyingReader(r, d)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(io.Discard, vr)
+	return err
+}
+
+// Reader computes the digests of the data read through it.
+type Reader struct {
+	r      io.Reader
+	algs   []Algorithm
+	hashes []hash.Hash
+}
+
+// NewReader returns a Reader that reads from r and computes the digests of algs.
+func NewReader(r io.Reader, algs ...Algorithm) (*Reader, error) {
+	hashes := make([]hash.Hash, 0, len(algs))
+	for _, alg := range algs {
+		h := alg.new()
+		if h == nil {
+			return nil, fmt.Errorf("digest: algorithm %q is not available", alg)
+		}
+		hashes = append(hashes, h)
+	}
+	return &Reader{
+		r:      r,
+		algs:   algs,
+		hashes: hashes,
+	}, nil
+}
+
+// Read implements io.Reader.
+func (r *Reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	for _, h := range r.hashes {
+		h.Write(p[:n])
+	}
+	return n, err
+}
+
+// Digests returns the digests of the data read so far.
+func (r *Reader) Digests() Digests {
+	d := make(Digests, 0, len(r.hashes))
+	for i, h := range r.hashes {
+		d = append(d, Digest{Algorithm: r.algs[i], Value: h.Sum(nil)})
+	}
+	return d
+}
+
+type verifyingReader struct {
+	r    *Reader
+	want Digests
+}
+
+// NewVerifyingReader returns a reader that reads from r, and verifies the data with d at the end.
+// Its Read returns ErrMismatch instead of io.EOF if the data doesn't match d.
+// The digests of the algorithms that are not available are ignored,
+// and it returns ErrNoAvailableAlgorithm if d has no available algorithm.
+func NewVerifyingReader(r io.Reader, d Digests) (io.Reader, error) {
+	var algs []Algorithm
+	var want Digests
+	for _, v := range d {
+		if v.Algorithm.Available() {
+			algs = append(algs, v.Algorithm)
+			want = append(want, v)
+		}
+	}
+	if len(algs) == 0 {
+		return nil, ErrNoAvailableAlgorithm
+	}
+	dr, err := NewReader(r, algs...)
+	if err != nil {
+		return nil, err
+	}
+	return &verifyingReader{r: dr, want: want}, nil
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err == io.EOF {
+		got := r.r.Digests()
+		for i, v := range r.want {
+			if subtle.ConstantTimeCompare(got[i].Value, v.Value) != 1 {
+				return n, ErrMismatch
+			}
+		}
+	}
+	return n, err
+}
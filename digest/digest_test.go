@@ -0,0 +1,143 @@
+package digest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// the example in RFC 9530 Appendix B.
+const (
+	exampleBody          = `{"hello": "world"}`
+	exampleSHA256        = "X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE="
+	exampleSHA512        = "WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyealdVLvRwEmTHWXvJwew=="
+	exampleContentDigest = "sha-256=:" + exampleSHA256 + ":, sha-512=:" + exampleSHA512 + ":"
+)
+
+func mustDecodeBase64(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestParse(t *testing.T) {
+	d, err := Parse([]string{exampleContentDigest})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Digests{
+		{Algorithm: SHA256, Value: mustDecodeBase64(t, exampleSHA256)},
+		{Algorithm: SHA512, Value: mustDecodeBase64(t, exampleSHA512)},
+	}
+	if !reflect.DeepEqual(d, want) {
+		t.Errorf("want %#v, got %#v", want, d)
+	}
+	if v, ok := d.Get(SHA512); !ok || !bytes.Equal(v, want[1].Value) {
+		t.Errorf("unexpected sha-512 digest: %x", v)
+	}
+	if _, ok := d.Get("md5"); ok {
+		t.Error("want not found, but found")
+	}
+
+	got, err := d.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != exampleContentDigest {
+		t.Errorf("want %q, got %q", exampleContentDigest, got)
+	}
+}
+
+func TestParse_invalid(t *testing.T) {
+	for _, field := range []string{
+		`sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=`,
+		`sha-256="X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE="`,
+		`sha-256`,
+	} {
+		if _, err := Parse([]string{field}); err == nil {
+			t.Errorf("%q: want error, but not", field)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	d, err := Compute(strings.NewReader(exampleBody), SHA256, SHA512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := d.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != exampleContentDigest {
+		t.Errorf("want %q, got %q", exampleContentDigest, got)
+	}
+
+	// sha-256 is the default.
+	d, err = Compute(strings.NewReader(exampleBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d) != 1 || d[0].Algorithm != SHA256 {
+		t.Errorf("unexpected digests: %#v", d)
+	}
+
+	if _, err := Compute(strings.NewReader(exampleBody), "md5"); err == nil {
+		t.Error("want error, but not")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	d, err := Parse([]string{exampleContentDigest + ", md5=:AAAA:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Verify(strings.NewReader(exampleBody)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := d.Verify(strings.NewReader(`{"hello": "world!"}`)); !errors.Is(err, ErrMismatch) {
+		t.Errorf("want ErrMismatch, got %v", err)
+	}
+
+	d, err = Parse([]string{`md5=:AAAA:`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Verify(strings.NewReader(exampleBody)); !errors.Is(err, ErrNoAvailableAlgorithm) {
+		t.Errorf("want ErrNoAvailableAlgorithm, got %v", err)
+	}
+}
+
+func TestNewVerifyingReader(t *testing.T) {
+	d, err := Parse([]string{"sha-256=:" + exampleSHA256 + ":"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewVerifyingReader(strings.NewReader(exampleBody), d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != exampleBody {
+		t.Errorf("want %q, got %q", exampleBody, body)
+	}
+
+	r, err = NewVerifyingReader(strings.NewReader(exampleBody[1:]), d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(r); !errors.Is(err, ErrMismatch) {
+		t.Errorf("want ErrMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package digest
+
+import (
+	"fmt"
+
+	"github.com/shogo82148/go-sfv"
+)
+
+// Preference is a member of the Want-Content-Digest and Want-Repr-Digest fields.
+type Preference struct {
+	Algorithm Algorithm
+
+	// Weight is the preference from 0 to 10.
+	// 0 means that the algorithm is not acceptable, and 10 means that it is the most preferred.
+	Weight int
+}
+
+// Preferences is the Want-Content-Digest or Want-Repr-Digest field.
+type Preferences []Preference
+
+// ParsePreferences parses the Want-Content-Digest or Want-Repr-Digest fields.
+// The parameters of the members are ignored.
+func ParsePreferences(fields []string) (Preferences, error) {
+	dict, err := sfv.DecodeDictionary(fields)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePreferencesDictionary(dict)
+}
+
+// ParsePreferencesDictionary converts dict into the preferences.
+func ParsePreferencesDictionary(dict sfv.Dictionary) (Preferences, error) {
+	if len(dict) == 0 {
+		return nil, nil
+	}
+	p := make(Preferences, 0, len(dict))
+	for _, member := range dict {
+		w, ok := member.Item.Value.(int64)
+		if !ok || w < 0 || w > 10 {
+			return nil, fmt.Errorf("digest: the preference of %q must be an integer from 0 to 10, got %v", member.Key, member.Item.Value)
+		}
+		p = append(p, Preference{Algorithm: Algorithm(member.Key), Weight: int(w)})
+	}
+	return p, nil
+}
+
+// Dictionary converts p into the Want-Content-Digest or Want-Repr-Digest field.
+func (p Preferences) Dictionary() (sfv.Dictionary, error) {
+	dict := make(sfv.Dictionary, 0, len(p))
+	for _, v := range p {
+		if !sfv.IsValidKey(string(v.Algorithm)) {
+			return nil, fmt.Errorf("digest: invalid algorithm %q", v.Algorithm)
+		}
+		if v.Weight < 0 || v.Weight > 10 {
+			return nil, fmt.Errorf("digest: the preference of %q must be from 0 to 10, got %d", v.Algorithm, v.Weight)
+		}
+		dict = append(dict, sfv.DictMember{Key: string(v.Algorithm), Item: sfv.Item{Value: int64(v.Weight)}})
+	}
+	return dict, nil
+}
+
+// Encode serializes p.
+func (p Preferences) Encode() (string, error) {
+	dict, err := p.Dictionary()
+	if err != nil {
+		return "", err
+	}
+	return sfv.EncodeDictionary(dict)
+}
+
+// Choose returns the most preferred algorithm in algs.
+// If algs is empty, the algorithms that this package can compute are used.
+// The algorithms with the weight 0 and the algorithms not in p are never chosen.
+// If the weights are same, the one that appears first in p is chosen,
+// so the preference of the sender wins over the order of the algorithms in algs.
+// The boolean result reports whether an algorithm is chosen.
+func (p Preferences) Choose(algs ...Algorithm) (Algorithm, bool) {
+	if len(algs) == 0 {
+		algs = availableAlgorithms
+	}
+
+	var ret Algorithm
+	var weight int
+	for _, v := range p {
+		if v.Weight <= weight {
+			continue
+		}
+		for _, alg := range algs {
+			if alg == v.Algorithm {
+				ret, weight = alg, v.Weight
+				break
+			}
+		}
+	}
+	return ret, weight > 0
+}
@@ -0,0 +1,70 @@
+package digest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePreferences(t *testing.T) {
+	// the example in RFC 9530 Section 4.
+	const field = "sha-512=3, sha-256=10, unixsum=0"
+	p, err := ParsePreferences([]string{field})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Preferences{
+		{Algorithm: SHA512, Weight: 3},
+		{Algorithm: SHA256, Weight: 10},
+		{Algorithm: "unixsum", Weight: 0},
+	}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("want %#v, got %#v", want, p)
+	}
+
+	got, err := p.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != field {
+		t.Errorf("want %q, got %q", field, got)
+	}
+
+	for _, field := range []string{
+		"sha-256=11",
+		"sha-256=-1",
+		"sha-256=1.0",
+		"sha-256",
+	} {
+		if _, err := ParsePreferences([]string{field}); err == nil {
+			t.Errorf("%q: want error, but not", field)
+		}
+	}
+}
+
+func TestPreferences_Choose(t *testing.T) {
+	tests := []struct {
+		field string
+		algs  []Algorithm
+		want  Algorithm
+		ok    bool
+	}{
+		{"sha-512=3, sha-256=10, unixsum=0", nil, SHA256, true},
+		{"sha-512=3, sha-256=10, unixsum=0", []Algorithm{SHA512}, SHA512, true},
+		{"sha-512=3, sha-256=3", nil, SHA512, true},
+		{"sha-256=3, sha-512=3", nil, SHA256, true},
+		{"sha-512=0, sha-256=0", nil, "", false},
+		{"unixsum=10", nil, "", false},
+		{"unixsum=10", []Algorithm{"unixsum"}, "unixsum", true},
+		{"", nil, "", false},
+	}
+	for _, tt := range tests {
+		p, err := ParsePreferences([]string{tt.field})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, ok := p.Choose(tt.algs...)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%q: want (%q, %t), got (%q, %t)", tt.field, tt.want, tt.ok, got, ok)
+		}
+	}
+}
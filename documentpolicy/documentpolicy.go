@@ -0,0 +1,220 @@
+// Package documentpolicy implements the Document-Policy and Require-Document-Policy fields
+// defined in WICG Document Policy.
+package documentpolicy
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/shogo82148/go-sfv"
+	"github.com/shogo82148/go-sfv/internal/fieldutil"
+)
+
+// The names of the fields.
+const (
+	FieldName            = "Document-Policy"
+	ReportOnlyFieldName  = "Document-Policy-Report-Only"
+	RequireFieldName     = "Require-Document-Policy"
+	SecRequiredFieldName = "Sec-Required-Document-Policy"
+)
+
+// ConfigurationPoint is a member of the Document-Policy field.
+type ConfigurationPoint struct {
+	// Name is the name of the configuration point, e.g. "oversized-images".
+	Name string
+
+	// Value is the value of the configuration point.
+	// It is bool, int64, float64 or sfv.Token.
+	Value sfv.Value
+
+	// ReportTo is the report-to parameter, or empty if it is omitted.
+	ReportTo string
+
+	// Extra is the parameters other than report-to.
+	Extra sfv.Parameters
+}
+
+// Policy is the Document-Policy field, or the Require-Document-Policy field.
+type Policy []ConfigurationPoint
+
+// Parse parses the Document-Policy fields.
+func Parse(fields []string) (Policy, error) {
+	dict, err := sfv.DecodeDictionary(fields)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDictionary(dict)
+}
+
+// ParseDictionary converts dict into the policy.
+// The members whose values are not booleans, integers, decimals or tokens are ignored,
+// so that the configuration points unknown to this package don't discard the others.
+func ParseDictionary(dict sfv.Dictionary) (Policy, error) {
+	if len(dict) == 0 {
+		return nil, nil
+	}
+	p := make(Policy, 0, len(dict))
+	for _, member := range dict {
+		c := ConfigurationPoint{
+			Name:  member.Key,
+			Value: member.Item.Value,
+		}
+		switch c.Value.(type) {
+		case bool, int64, float64, sfv.Token:
+		default:
+			continue
+		}
+
+		for _, param := range member.Item.Parameters {
+			if param.Key != "report-to" {
+				c.Extra = append(c.Extra, param)
+				continue
+			}
+			var ok bool
+			if c.ReportTo, ok = fieldutil.StringOf(param.Value); !ok {
+				return nil, fmt.Errorf("documentpolicy: invalid parameter report-to=%v of %q", param.Value, c.Name)
+			}
+		}
+		p = append(p, c)
+	}
+	return p, nil
+}
+
+// Validate checks that c can be serialized as a member of the Document-Policy field.
+func (c ConfigurationPoint) Validate() error {
+	if !sfv.IsValidKey(c.Name) {
+		return fmt.Errorf("documentpolicy: invalid configuration point %q", c.Name)
+	}
+	switch c.Value.(type) {
+	case bool, int64, float64, sfv.Token:
+	default:
+		return fmt.Errorf("documentpolicy: the value of %q must be a boolean, an integer, a decimal or a token, got %T", c.Name, c.Value)
+	}
+	if key, ok := fieldutil.ReservedParam(c.Extra, "report-to"); ok {
+		return fmt.Errorf("documentpolicy: %q has the parameter %q in Extra", c.Name, key)
+	}
+	return nil
+}
+
+// Item converts c into the member value of the Document-Policy field.
+func (c ConfigurationPoint) Item() (sfv.Item, error) {
+	if err := c.Validate(); err != nil {
+		return sfv.Item{}, err
+	}
+
+	item := sfv.Item{
+		Value: c.Value,
+	}
+	if c.ReportTo != "" {
+		item.Parameters = append(item.Parameters, sfv.Parameter{Key: "report-to", Value: fieldutil.TokenOrString(c.ReportTo)})
+	}
+	item.Parameters = append(item.Parameters, c.Extra...)
+
+	if err := sfv.ValidateItem(item); err != nil {
+		return sfv.Item{}, err
+	}
+	return item, nil
+}
+
+// Dictionary converts p into the Document-Policy field.
+func (p Policy) Dictionary() (sfv.Dictionary, error) {
+	dict := make(sfv.Dictionary, 0, len(p))
+	for _, c := range p {
+		item, err := c.Item()
+		if err != nil {
+			return nil, err
+		}
+		dict = append(dict, sfv.DictMember{Key: c.Name, Item: item})
+	}
+	return dict, nil
+}
+
+// Encode serializes p.
+func (p Policy) Encode() (string, error) {
+	dict, err := p.Dictionary()
+	if err != nil {
+		return "", err
+	}
+	return sfv.EncodeDictionary(dict)
+}
+
+// Lookup returns the last configuration point of the name, that is the one in effect.
+func (p Policy) Lookup(name string) (ConfigurationPoint, bool) {
+	for i := len(p) - 1; i >= 0; i-- {
+		if p[i].Name == name {
+			return p[i], true
+		}
+	}
+	return ConfigurationPoint{}, false
+}
+
+// Satisfies reports whether p is compatible with the required policy,
+// that is every configuration point in required is satisfied by the equal or stricter one in p.
+func (p Policy) Satisfies(required Policy) bool {
+	return len(p.Unsatisfied(required)) == 0
+}
+
+// Unsatisfied returns the names of the configuration points in required that p doesn't satisfy.
+//
+// The values are compared as follows:
+//   - Booleans: ?0 is stricter than ?1. The configuration point that is not in p is treated as ?1.
+//   - Integers and Decimals: the lower value is stricter. The configuration point that is not in p is unlimited.
+//     They are compared by value, e.g. 5.0 satisfies 5,
+//     and the decimals are compared after rounded to three decimal places, as they are serialized.
+//   - Tokens: they must be same.
+//
+// Otherwise, if the types of the values are different, the required one is not satisfied.
+func (p Policy) Unsatisfied(required Policy) []string {
+	var ret []string
+	for i, req := range required {
+		if _, ok := required[i+1:].Lookup(req.Name); ok {
+			// it is overridden by the later member.
+			continue
+		}
+		var v sfv.Value
+		if c, ok := p.Lookup(req.Name); ok {
+			v = c.Value
+		} else if _, ok := req.Value.(bool); ok {
+			// the boolean configuration points allow the features by default.
+			v = true
+		}
+		if !satisfies(v, req.Value) {
+			ret = append(ret, req.Name)
+		}
+	}
+	return ret
+}
+
+// satisfies reports whether v is equal to or stricter than required.
+func satisfies(v, required sfv.Value) bool {
+	switch required := required.(type) {
+	case bool:
+		v, ok := v.(bool)
+		return ok && (!v || required)
+	case int64, float64:
+		n, ok := number(v)
+		r, _ := number(required)
+		return ok && n <= r
+	case sfv.Token:
+		v, ok := v.(sfv.Token)
+		return ok && v == required
+	}
+	return false
+}
+
+// number returns the value of an Integer or a Decimal.
+// Integers are exact in float64, because they have at most 15 digits.
+func number(v sfv.Value) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return roundDecimal(v), true
+	}
+	return 0, false
+}
+
+// roundDecimal rounds v to three decimal places, as RFC 9651 Section 4.1.5 does.
+func roundDecimal(v float64) float64 {
+	return math.RoundToEven(v*1000) / 1000
+}
@@ -0,0 +1,151 @@
+package documentpolicy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/shogo82148/go-sfv"
+)
+
+func TestParse(t *testing.T) {
+	const field = `oversized-images=2.0;report-to=main, unsized-media=?0, max-frames=5, image-compression=lossless;report-to="the endpoint";x=1, document-write`
+	p, err := Parse([]string{field})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Policy{
+		{Name: "oversized-images", Value: 2.0, ReportTo: "main"},
+		{Name: "unsized-media", Value: false},
+		{Name: "max-frames", Value: int64(5)},
+		{Name: "image-compression", Value: sfv.Token("lossless"), ReportTo: "the endpoint", Extra: sfv.Parameters{{Key: "x", Value: int64(1)}}},
+		{Name: "document-write", Value: true},
+	}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("want %#v, got %#v", want, p)
+	}
+
+	got, err := p.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != field {
+		t.Errorf("want %q, got %q", field, got)
+	}
+
+	if c, ok := p.Lookup("max-frames"); !ok || c.Value != int64(5) {
+		t.Errorf("unexpected configuration point: %#v", c)
+	}
+	if _, ok := p.Lookup("sync-script"); ok {
+		t.Error("want not found, but found")
+	}
+}
+
+func TestParse_unsupportedValue(t *testing.T) {
+	p, err := Parse([]string{`oversized-images="2.0", max-frames=5, unsized-media=:AAAA:, foo=(2.0)`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Policy{{Name: "max-frames", Value: int64(5)}}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("want %#v, got %#v", want, p)
+	}
+}
+
+func TestParse_invalid(t *testing.T) {
+	for _, field := range []string{
+		`oversized-images=2.0;report-to=1`,
+		`Oversized-images=2.0`,
+	} {
+		if _, err := Parse([]string{field}); err == nil {
+			t.Errorf("%q: want error, but not", field)
+		}
+	}
+}
+
+func TestPolicy_Encode_invalid(t *testing.T) {
+	for _, p := range []Policy{
+		{{Name: "Oversized-images", Value: 2.0}},
+		{{Name: "oversized-images", Value: "2.0"}},
+		{{Name: "oversized-images", Value: 1e12}},
+		{{Name: "oversized-images", Value: 2.0, Extra: sfv.Parameters{{Key: "report-to", Value: "main"}}}},
+	} {
+		if _, err := p.Encode(); err == nil {
+			t.Errorf("%#v: want error, but not", p)
+		}
+	}
+}
+
+func TestPolicy_Unsatisfied(t *testing.T) {
+	tests := []struct {
+		response string
+		required string
+		want     []string
+	}{
+		// booleans
+		{`unsized-media=?0`, `unsized-media=?0`, nil},
+		{`unsized-media=?0`, `unsized-media`, nil},
+		{`unsized-media`, `unsized-media=?0`, []string{"unsized-media"}},
+		{``, `unsized-media=?0`, []string{"unsized-media"}},
+		{``, `unsized-media`, nil},
+
+		// integers
+		{`max-frames=5`, `max-frames=5`, nil},
+		{`max-frames=3`, `max-frames=5`, nil},
+		{`max-frames=6`, `max-frames=5`, []string{"max-frames"}},
+		{``, `max-frames=5`, []string{"max-frames"}},
+
+		// decimals
+		{`oversized-images=2.0`, `oversized-images=2.0`, nil},
+		{`oversized-images=1.5`, `oversized-images=2.0`, nil},
+		{`oversized-images=2.001`, `oversized-images=2.0`, []string{"oversized-images"}},
+		{``, `oversized-images=2.0`, []string{"oversized-images"}},
+
+		// tokens
+		{`image-compression=lossless`, `image-compression=lossless`, nil},
+		{`image-compression=lossy`, `image-compression=lossless`, []string{"image-compression"}},
+
+		// integers and decimals are compared by value
+		{`max-frames=5.0`, `max-frames=5`, nil},
+		{`max-frames=5.5`, `max-frames=5`, []string{"max-frames"}},
+		{`oversized-images=2`, `oversized-images=2.0`, nil},
+
+		// the types are different
+		{`max-frames=?0`, `max-frames=5`, []string{"max-frames"}},
+		{`image-compression=5`, `image-compression=lossless`, []string{"image-compression"}},
+
+		// multiple configuration points
+		{
+			`oversized-images=1.0, unsized-media=?0, max-frames=10`,
+			`unsized-media=?0, max-frames=5, oversized-images=2.0, document-write=?0`,
+			[]string{"max-frames", "document-write"},
+		},
+	}
+	for _, tt := range tests {
+		response, err := Parse([]string{tt.response})
+		if err != nil {
+			t.Fatal(err)
+		}
+		required, err := Parse([]string{tt.required})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := response.Unsatisfied(required)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("response %q, required %q: want %q, got %q", tt.response, tt.required, tt.want, got)
+		}
+		if response.Satisfies(required) != (len(tt.want) == 0) {
+			t.Errorf("response %q, required %q: unexpected result of Satisfies", tt.response, tt.required)
+		}
+	}
+}
+
+func TestPolicy_Unsatisfied_overridden(t *testing.T) {
+	response := Policy{{Name: "max-frames", Value: int64(5)}}
+	required := Policy{
+		{Name: "max-frames", Value: int64(1)},
+		{Name: "max-frames", Value: int64(10)},
+	}
+	if got := response.Unsatisfied(required); len(got) != 0 {
+		t.Errorf("want no unsatisfied configuration points, got %q", got)
+	}
+}
@@ -0,0 +1,286 @@
+package sfv
+
+import "strings"
+
+// fieldEditor keeps the original text of the members of a list or a dictionary,
+// and the text between them.
+type fieldEditor struct {
+	prefix  string
+	suffix  string
+	members []string
+
+	// seps[i] is the text between members[i] and members[i+1].
+	seps []string
+}
+
+// newFieldEditor splits the joined field lines into members at the spans.
+func newFieldEditor(fields []string, spans []Span) fieldEditor {
+	joined := strings.Join(fields, ", ")
+	if len(spans) == 0 {
+		return fieldEditor{}
+	}
+
+	// the offsets of the field lines in the joined text.
+	offsets := make([]int, len(fields))
+	for i := 1; i < len(fields); i++ {
+		offsets[i] = offsets[i-1] + len(fields[i-1]) + len(", ")
+	}
+	offset := func(pos Pos) int {
+		return offsets[pos.Line] + pos.Offset
+	}
+
+	e := fieldEditor{
+		prefix:  joined[:offset(spans[0].Start)],
+		suffix:  joined[offset(spans[len(spans)-1].End):],
+		members: make([]string, 0, len(spans)),
+		seps:    make([]string, 0, len(spans)-1),
+	}
+	for i, span := range spans {
+		e.members = append(e.members, joined[offset(span.Start):offset(span.End)])
+		if i+1 < len(spans) {
+			e.seps = append(e.seps, joined[offset(span.End):offset(spans[i+1].Start)])
+		}
+	}
+	return e
+}
+
+func (e *fieldEditor) set(i int, text string) {
+	e.members[i] = text
+}
+
+func (e *fieldEditor) insert(i int, text string) {
+	if len(e.members) == 0 {
+		e.members = []string{text}
+		return
+	}
+	e.members = append(e.members, "")
+	copy(e.members[i+1:], e.members[i:])
+	e.members[i] = text
+
+	if i == len(e.members)-1 {
+		// it is the last member, insert the separator before the member.
+		e.seps = append(e.seps, ", ")
+		return
+	}
+	e.seps = append(e.seps, "")
+	copy(e.seps[i+1:], e.seps[i:])
+	e.seps[i] = ", "
+}
+
+func (e *fieldEditor) delete(i int) {
+	e.members = append(e.members[:i], e.members[i+1:]...)
+	switch {
+	case len(e.seps) == 0:
+		// it was the only member.
+	case i < len(e.seps):
+		// remove the separator after the member.
+		e.seps = append(e.seps[:i], e.seps[i+1:]...)
+	default:
+		// it was the last member, remove the separator before the member.
+		e.seps = e.seps[:i-1]
+	}
+}
+
+func (e *fieldEditor) String() string {
+	if len(e.members) == 0 {
+		return ""
+	}
+	var buf strings.Builder
+	buf.WriteString(e.prefix)
+	for i, member := range e.members {
+		buf.WriteString(member)
+		if i < len(e.seps) {
+			buf.WriteString(e.seps[i])
+		}
+	}
+	buf.WriteString(e.suffix)
+	return buf.String()
+}
+
+// ListEditor edits a received List field
+// while keeping the untouched members byte-for-byte identical.
+type ListEditor struct {
+	e     fieldEditor
+	items []Item
+}
+
+// EditList decodes fields as a List, and returns an editor of it.
+func EditList(fields []string) (*ListEditor, error) {
+	list, err := DecodeListSyntax(fields)
+	if err != nil {
+		return nil, err
+	}
+	spans := make([]Span, 0, len(list.Members))
+	items := make([]Item, 0, len(list.Members))
+	for i := range list.Members {
+		spans = append(spans, list.Members[i].Span)
+		items = append(items, list.Members[i].Item())
+	}
+	return &ListEditor{
+		e:     newFieldEditor(fields, spans),
+		items: items,
+	}, nil
+}
+
+// Len returns the number of the members.
+func (e *ListEditor) Len() int {
+	return len(e.items)
+}
+
+// Item returns the i-th member.
+func (e *ListEditor) Item(i int) Item {
+	return e.items[i]
+}
+
+// Set replaces the i-th member with item.
+func (e *ListEditor) Set(i int, item Item) error {
+	text, err := EncodeList(List{item})
+	if err != nil {
+		return err
+	}
+	e.e.set(i, text)
+	e.items[i] = item
+	return nil
+}
+
+// Insert inserts item at the index i.
+func (e *ListEditor) Insert(i int, item Item) error {
+	text, err := EncodeList(List{item})
+	if err != nil {
+		return err
+	}
+	e.e.insert(i, text)
+	e.items = append(e.items, Item{})
+	copy(e.items[i+1:], e.items[i:])
+	e.items[i] = item
+	return nil
+}
+
+// Append appends item to the end of the list.
+func (e *ListEditor) Append(item Item) error {
+	return e.Insert(len(e.items), item)
+}
+
+// Delete removes the i-th member.
+func (e *ListEditor) Delete(i int) {
+	e.e.delete(i)
+	e.items = append(e.items[:i], e.items[i+1:]...)
+}
+
+// List returns the edited list.
+func (e *ListEditor) List() List {
+	if len(e.items) == 0 {
+		return nil
+	}
+	return append(List(nil), e.items...)
+}
+
+// String returns the edited field value.
+// The field lines are joined with ", ".
+// It returns an empty string if the list has no members.
+func (e *ListEditor) String() string {
+	return e.e.String()
+}
+
+// DictionaryEditor edits a received Dictionary field
+// while keeping the untouched members byte-for-byte identical.
+// Duplicated keys are kept as they are received.
+type DictionaryEditor struct {
+	e       fieldEditor
+	members []DictMember
+}
+
+// EditDictionary decodes fields as a Dictionary, and returns an editor of it.
+func EditDictionary(fields []string) (*DictionaryEditor, error) {
+	dict, err := DecodeDictionarySyntax(fields)
+	if err != nil {
+		return nil, err
+	}
+	spans := make([]Span, 0, len(dict.Members))
+	members := make([]DictMember, 0, len(dict.Members))
+	for i := range dict.Members {
+		spans = append(spans, dict.Members[i].Span)
+		members = append(members, DictMember{
+			Key:  dict.Members[i].Key.Text,
+			Item: dict.Members[i].Item.Item(),
+		})
+	}
+	return &DictionaryEditor{
+		e:       newFieldEditor(fields, spans),
+		members: members,
+	}, nil
+}
+
+// lastIndex returns the index of the last member associated with key.
+func (e *DictionaryEditor) lastIndex(key string) int {
+	for i := len(e.members) - 1; i >= 0; i-- {
+		if e.members[i].Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// Get returns the last item associated with the given key.
+func (e *DictionaryEditor) Get(key string) (Item, bool) {
+	i := e.lastIndex(key)
+	if i < 0 {
+		return Item{}, false
+	}
+	return e.members[i].Item, true
+}
+
+// Set sets the item associated with the given key.
+// If the key already exists, the last member with the key is replaced in place.
+// Otherwise, a new member is appended to the end of the dictionary.
+func (e *DictionaryEditor) Set(key string, item Item) error {
+	member := DictMember{
+		Key:  key,
+		Item: item,
+	}
+	text, err := EncodeDictionary(Dictionary{member})
+	if err != nil {
+		return err
+	}
+	if i := e.lastIndex(key); i >= 0 {
+		e.e.set(i, text)
+		e.members[i] = member
+		return nil
+	}
+	e.e.insert(len(e.members), text)
+	e.members = append(e.members, member)
+	return nil
+}
+
+// Delete removes all the members associated with the given key.
+func (e *DictionaryEditor) Delete(key string) {
+	for i := len(e.members) - 1; i >= 0; i-- {
+		if e.members[i].Key == key {
+			e.e.delete(i)
+			e.members = append(e.members[:i], e.members[i+1:]...)
+		}
+	}
+}
+
+// Dictionary returns the edited dictionary.
+// The value of duplicated keys are overwritten in the same way as DecodeDictionary.
+func (e *DictionaryEditor) Dictionary() Dictionary {
+	var ret Dictionary
+	seenKeys := map[string]int{}
+	for _, member := range e.members {
+		if i, ok := seenKeys[member.Key]; ok {
+			ret[i].Item = member.Item
+		} else {
+			seenKeys[member.Key] = len(ret)
+			ret = append(ret, member)
+		}
+	}
+	return ret
+}
+
+// String returns the edited field value.
+// The field lines are joined with ", ".
+// It returns an empty string if the dictionary has no members.
+func (e *DictionaryEditor) String() string {
+	return e.e.String()
+}
@@ -0,0 +1,170 @@
+package sfv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestListEditor(t *testing.T) {
+	e, err := EditList([]string{`a;x=1.50,   "b";y`, `(c   d)`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Len() != 3 {
+		t.Fatalf("want 3 members, got %d", e.Len())
+	}
+	if got := e.Item(1).Value; got != "b" {
+		t.Errorf("want %q, got %v", "b", got)
+	}
+
+	// append a new member
+	if err := e.Append(Item{Value: Token("e"), Parameters: Parameters{{Key: "z", Value: 2.5}}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := e.String(), `a;x=1.50,   "b";y, (c   d), e;z=2.5`; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	// replace a member
+	if err := e.Set(1, Item{Value: "B"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := e.String(), `a;x=1.50,   "B", (c   d), e;z=2.5`; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	// insert a member
+	if err := e.Insert(0, Item{Value: int64(0)}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := e.String(), `0, a;x=1.50,   "B", (c   d), e;z=2.5`; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	// delete members
+	e.Delete(2)
+	if got, want := e.String(), `0, a;x=1.50,   (c   d), e;z=2.5`; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	e.Delete(3)
+	if got, want := e.String(), `0, a;x=1.50,   (c   d)`; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	want := List{
+		{Value: int64(0)},
+		{Value: Token("a"), Parameters: Parameters{{Key: "x", Value: 1.5}}},
+		{Value: InnerList{{Value: Token("c")}, {Value: Token("d")}}},
+	}
+	if got := e.List(); !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+	decoded, err := DecodeList([]string{e.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, want) {
+		t.Errorf("want %v, got %v", want, decoded)
+	}
+
+	// delete all members
+	e.Delete(0)
+	e.Delete(0)
+	e.Delete(0)
+	if got := e.String(); got != "" {
+		t.Errorf("want empty, got %q", got)
+	}
+	if err := e.Append(Item{Value: Token("f")}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := e.String(), "f"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestListEditor_empty(t *testing.T) {
+	e, err := EditList(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Append(Item{Value: Token("a")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Append(Item{Value: Token("b")}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := e.String(), "a, b"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestListEditor_invalid(t *testing.T) {
+	if _, err := EditList([]string{"a,"}); err == nil {
+		t.Error("want error, but not")
+	}
+
+	e, err := EditList([]string{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Append(Item{Value: Token("")}); err == nil {
+		t.Error("want error, but not")
+	}
+	if got, want := e.String(), "a"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestDictionaryEditor(t *testing.T) {
+	e, err := EditDictionary([]string{`a=1.50,b;x ,  c=?0`, `a=2`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item, ok := e.Get("a"); !ok || item.Value != int64(2) {
+		t.Errorf("want 2, got %v", item.Value)
+	}
+
+	// delete a member
+	e.Delete("b")
+	if got, want := e.String(), `a=1.50,c=?0, a=2`; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	// replace the last duplicated member
+	if err := e.Set("a", Item{Value: int64(3)}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := e.String(), `a=1.50,c=?0, a=3`; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	// add a new member
+	if err := e.Set("d", Item{Value: true, Parameters: Parameters{{Key: "y", Value: Token("z")}}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := e.String(), `a=1.50,c=?0, a=3, d;y=z`; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	// delete all duplicated members
+	e.Delete("a")
+	if got, want := e.String(), `c=?0, d;y=z`; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	if _, ok := e.Get("a"); ok {
+		t.Error("want a to be deleted")
+	}
+
+	want := Dictionary{
+		{Key: "c", Item: Item{Value: false}},
+		{Key: "d", Item: Item{Value: true, Parameters: Parameters{{Key: "y", Value: Token("z")}}}},
+	}
+	if got := e.Dictionary(); !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// invalid key
+	if err := e.Set("INVALID", Item{Value: true}); err == nil {
+		t.Error("want error, but not")
+	}
+}
@@ -220,6 +220,15 @@ func (s *encodeState) encodeBareItem(v Value) error {
 		s.buf.WriteByte('%')
 		return s.encodeDisplayString(string(v))
 
+	case Raw:
+		if v.isInnerList() {
+			return fmt.Errorf("sfv: raw value %q is an inner list, that is not allowed here", v)
+		}
+		if !v.Valid() {
+			return fmt.Errorf("sfv: raw value %q is not a valid bare item", v)
+		}
+		s.buf.WriteString(string(v))
+
 	default:
 		return fmt.Errorf("sfv: unsupported type: %T", v)
 	}
@@ -248,14 +257,9 @@ func (s *encodeState) encodeKey(key string) error {
 	if len(key) == 0 {
 		return errors.New("sfv: key is an empty string")
 	}
-	if (key[0] < 'a' || key[0] > 'z') && key[0] != '*' {
+	if !IsValidKey(key) {
 		return fmt.Errorf("sfv: key %q has invalid characters", key)
 	}
-	for _, ch := range []byte(key[1:]) {
-		if !validKeyChars[ch] {
-			return fmt.Errorf("sfv: key %q has invalid characters", key)
-		}
-	}
 
 	// encode the key
 	s.buf.WriteString(key)
@@ -263,8 +267,15 @@ func (s *encodeState) encodeKey(key string) error {
 }
 
 func (s *encodeState) encodeBareItemOrInnerList(value Value) error {
-	if list, ok := value.(InnerList); ok {
-		return s.encodeInnerList(list)
+	switch v := value.(type) {
+	case InnerList:
+		return s.encodeInnerList(v)
+	case Raw:
+		if !v.Valid() {
+			return fmt.Errorf("sfv: raw value %q is not a valid bare item or inner list", v)
+		}
+		s.buf.WriteString(string(v))
+		return nil
 	}
 	return s.encodeBareItem(value)
 }
@@ -354,3 +365,102 @@ func EncodeDictionary(dict Dictionary) (string, error) {
 	}
 	return state.buf.String(), nil
 }
+
+// encodeMembers serializes each member of a list or a dictionary separately.
+// encodeMember is called with the index of the member to be serialized.
+func encodeMembers(n int, encodeMember func(s *encodeState, i int) error) ([]string, error) {
+	state := getEncodeState()
+	defer putEncodeState(state)
+
+	members := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		state.buf.Reset()
+		if err := encodeMember(state, i); err != nil {
+			return nil, err
+		}
+		members = append(members, state.buf.String())
+	}
+	return members, nil
+}
+
+// packLines joins the members into field lines that are not longer than maxLen bytes.
+// If maxLen is zero or negative, each member is put on its own field line.
+func packLines(members []string, maxLen int) ([]string, error) {
+	var lines []string
+	var buf []byte
+	for _, member := range members {
+		if maxLen > 0 && len(member) > maxLen {
+			return nil, fmt.Errorf("sfv: member %q exceeds the line length limit %d", member, maxLen)
+		}
+		if len(buf) > 0 && (maxLen <= 0 || len(buf)+len(", ")+len(member) > maxLen) {
+			lines = append(lines, string(buf))
+			buf = buf[:0]
+		}
+		if len(buf) > 0 {
+			buf = append(buf, ", "...)
+		}
+		buf = append(buf, member...)
+	}
+	if len(buf) > 0 {
+		lines = append(lines, string(buf))
+	}
+	return lines, nil
+}
+
+// EncodeListLines encodes the given list to Structured Field Values,
+// and splits the result on member boundaries into field lines that are not longer than maxLen bytes.
+// If maxLen is zero or negative, each member is put on its own field line.
+// It returns an error if a member is longer than maxLen.
+//
+// DecodeList reproduces the same list from the field lines.
+func EncodeListLines(list List, maxLen int) ([]string, error) {
+	members, err := encodeMembers(len(list), func(s *encodeState, i int) error {
+		return s.encodeList(list[i : i+1])
+	})
+	if err != nil {
+		return nil, err
+	}
+	return packLines(members, maxLen)
+}
+
+// EncodeDictionaryLines encodes the given dictionary to Structured Field Values,
+// and splits the result on member boundaries into field lines that are not longer than maxLen bytes.
+// If maxLen is zero or negative, each member is put on its own field line.
+// It returns an error if a member is longer than maxLen.
+//
+// DecodeDictionary reproduces the same dictionary from the field lines.
+func EncodeDictionaryLines(dict Dictionary, maxLen int) ([]string, error) {
+	members, err := encodeMembers(len(dict), func(s *encodeState, i int) error {
+		return s.encodeDictionary(dict[i : i+1])
+	})
+	if err != nil {
+		return nil, err
+	}
+	return packLines(members, maxLen)
+}
+
+// AppendKey appends the serialization of key to dst according to RFC 9651 Section 4.1.1.3,
+// and returns the extended buffer.
+// If key is invalid, it returns dst unchanged and an error.
+func AppendKey(dst []byte, key string) ([]byte, error) {
+	state := getEncodeState()
+	defer putEncodeState(state)
+
+	if err := state.encodeKey(key); err != nil {
+		return dst, err
+	}
+	return append(dst, state.buf.Bytes()...), nil
+}
+
+// AppendBareItem appends the serialization of v to dst according to RFC 9651 Section 4.1.3.1,
+// and returns the extended buffer.
+// If v cannot be serialized, it returns dst unchanged and an error.
+func AppendBareItem(dst []byte, v Value) ([]byte, error) {
+	state := getEncodeState()
+	defer putEncodeState(state)
+
+	if err := state.encodeBareItem(v); err != nil {
+		return dst, err
+	}
+	return append(dst, state.buf.Bytes()...), nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
@@ -431,6 +432,82 @@ func readDictionary(v interface{}) (Dictionary, error) {
 	return ret, nil
 }
 
+func TestEncodeRaw(t *testing.T) {
+	list := List{
+		{
+			Value: Raw(`(1 2 "received from upstream")`),
+			Parameters: Parameters{
+				{Key: "a", Value: int64(1)},
+			},
+		},
+		{
+			Value: InnerList{
+				{Value: Raw("1.50")},
+			},
+		},
+	}
+	val, err := EncodeList(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `(1 2 "received from upstream");a=1, (1.50)`; val != want {
+		t.Errorf("want %q, got %q", want, val)
+	}
+
+	dict := Dictionary{
+		{Key: "a", Item: Item{Value: Raw("?1")}},
+		{Key: "b", Item: Item{Value: int64(1), Parameters: Parameters{{Key: "c", Value: Raw(":AQID:")}}}},
+	}
+	val, err = EncodeDictionary(dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `a=?1, b=1;c=:AQID:`; val != want {
+		t.Errorf("want %q, got %q", want, val)
+	}
+}
+
+func TestEncodeRaw_invalid(t *testing.T) {
+	// inner lists are not allowed in items, inner lists and parameters.
+	if _, err := EncodeItem(Item{Value: Raw("(1 2)")}); err == nil {
+		t.Error("item: want error, but not")
+	}
+	if _, err := EncodeList(List{{Value: InnerList{{Value: Raw("(1 2)")}}}}); err == nil {
+		t.Error("inner list: want error, but not")
+	}
+	if _, err := EncodeList(List{{Value: int64(1), Parameters: Parameters{{Key: "p", Value: Raw("(1)")}}}}); err == nil {
+		t.Error("parameter: want error, but not")
+	}
+	if _, err := AppendBareItem(nil, Raw("(1)")); err == nil {
+		t.Error("AppendBareItem: want error, but not")
+	}
+
+	// the values out of the grammar are not allowed anywhere.
+	for _, raw := range []Raw{
+		"a\r\nX-Injected: 1",
+		"1\n",
+		"",
+		" 1",
+		"1;a",
+		"1, 2",
+		"(1);a",
+		`"unterminated`,
+	} {
+		if _, err := EncodeItem(Item{Value: raw}); err == nil {
+			t.Errorf("item %q: want error, but not", raw)
+		}
+		if _, err := EncodeList(List{{Value: raw}}); err == nil {
+			t.Errorf("list %q: want error, but not", raw)
+		}
+		if _, err := EncodeDictionary(Dictionary{{Key: "a", Item: Item{Value: raw}}}); err == nil {
+			t.Errorf("dictionary %q: want error, but not", raw)
+		}
+		if _, err := AppendBareItem(nil, raw); err == nil {
+			t.Errorf("AppendBareItem %q: want error, but not", raw)
+		}
+	}
+}
+
 func TestEncode_invalidTypes(t *testing.T) {
 	var err error
 
@@ -535,6 +612,103 @@ func TestEncode_invalidTypes(t *testing.T) {
 	}
 }
 
+func TestEncodeListLines(t *testing.T) {
+	var list List
+	for i := 0; i < 1000; i++ {
+		list = append(list, Item{
+			Value: fmt.Sprintf("member-%d", i),
+			Parameters: Parameters{
+				{Key: "index", Value: int64(i)},
+			},
+		})
+	}
+	list = append(list, Item{
+		Value: InnerList{
+			{Value: Token("a")},
+			{Value: Token("b")},
+		},
+	})
+
+	lines, err := EncodeListLines(list, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) < 2 {
+		t.Errorf("want multiple lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if len(line) > 100 {
+			t.Errorf("the line %q is too long", line)
+		}
+	}
+
+	got, err := DecodeList(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, list) {
+		t.Error("the decoded list is different from the original")
+	}
+
+	// a member is longer than the limit.
+	if _, err := EncodeListLines(list, 10); err == nil {
+		t.Error("want error, but not")
+	}
+
+	// empty list
+	lines, err = EncodeListLines(nil, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 0 {
+		t.Errorf("want no lines, got %q", lines)
+	}
+}
+
+func TestEncodeDictionaryLines(t *testing.T) {
+	var dict Dictionary
+	for i := 0; i < 1000; i++ {
+		dict = append(dict, DictMember{
+			Key: fmt.Sprintf("key%d", i),
+			Item: Item{
+				Value: int64(i),
+			},
+		})
+	}
+
+	lines, err := EncodeDictionaryLines(dict, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range lines {
+		if len(line) > 64 {
+			t.Errorf("the line %q is too long", line)
+		}
+	}
+
+	got, err := DecodeDictionary(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, dict) {
+		t.Error("the decoded dictionary is different from the original")
+	}
+
+	// one member per line
+	lines, err = EncodeDictionaryLines(dict[:3], 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"key0=0", "key1=1", "key2=2"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("want %q, got %q", want, lines)
+	}
+
+	// invalid key
+	if _, err := EncodeDictionaryLines(Dictionary{{Key: "INVALID", Item: Item{Value: true}}}, 0); err == nil {
+		t.Error("want error, but not")
+	}
+}
+
 func BenchmarkEncodeInteger(b *testing.B) {
 	item := Item{
 		Value: int64(-MaxInteger),
@@ -772,3 +946,56 @@ func BenchmarkEncodeDictionary(b *testing.B) {
 		}
 	}
 }
+
+func TestAppendKey(t *testing.T) {
+	got, err := AppendKey([]byte("x;"), "a*b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "x;a*b" {
+		t.Errorf("want %q, got %q", "x;a*b", got)
+	}
+
+	for _, key := range []string{"", "A", "a b"} {
+		got, err := AppendKey([]byte("x"), key)
+		if err == nil {
+			t.Errorf("%q: want error, but not", key)
+		}
+		if string(got) != "x" {
+			t.Errorf("%q: want %q, got %q", key, "x", got)
+		}
+	}
+}
+
+func TestAppendBareItem(t *testing.T) {
+	tests := []struct {
+		in   Value
+		want string
+	}{
+		{int64(42), "42"},
+		{1.5, "1.5"},
+		{"foo", `"foo"`},
+		{Token("foo/bar"), "foo/bar"},
+		{[]byte{1, 2, 3}, ":AQID:"},
+		{true, "?1"},
+		{DisplayString("café"), `%"caf%c3%a9"`},
+	}
+	for _, tt := range tests {
+		got, err := AppendBareItem([]byte("x="), tt.in)
+		if err != nil {
+			t.Errorf("%#v: unexpected error: %v", tt.in, err)
+			continue
+		}
+		if string(got) != "x="+tt.want {
+			t.Errorf("%#v: want %q, got %q", tt.in, "x="+tt.want, got)
+		}
+	}
+
+	got, err := AppendBareItem([]byte("x="), Token("1"))
+	if err == nil {
+		t.Error("want error, but not")
+	}
+	if string(got) != "x=" {
+		t.Errorf("want %q, got %q", "x=", got)
+	}
+}
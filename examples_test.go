@@ -2,6 +2,7 @@ package sfv_test
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/shogo82148/go-sfv"
@@ -26,6 +27,31 @@ func ExampleEncodeList() {
 	// foo, bar
 }
 
+func ExampleEncodeListLines() {
+	list := sfv.List{
+		{
+			Value: sfv.Token("foo"),
+		},
+		{
+			Value: sfv.Token("bar"),
+		},
+		{
+			Value: sfv.Token("baz"),
+		},
+	}
+	lines, err := sfv.EncodeListLines(list, 8)
+	if err != nil {
+		panic(err)
+	}
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+
+	//Output:
+	// foo, bar
+	// baz
+}
+
 func ExampleDecodeList() {
 	h := make(http.Header)
 	h.Add("Example-Hdr", "foo")
@@ -210,3 +236,109 @@ func ExampleDictionary_Len() {
 	//Output:
 	// 2
 }
+
+func ExampleGetDictionary() {
+	h := make(http.Header)
+	h.Add("Example-Dict", "a=1, b=2")
+
+	dict, err := sfv.GetDictionary(h, "example-dict")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(dict.Get("b").Value)
+
+	//Output:
+	// 2
+}
+
+func ExampleSetList() {
+	h := make(http.Header)
+	list := sfv.List{
+		{
+			Value: sfv.Token("foo"),
+		},
+		{
+			Value: sfv.Token("bar"),
+		},
+	}
+	if err := sfv.SetList(h, "example-list", list); err != nil {
+		panic(err)
+	}
+	fmt.Println(h.Get("Example-List"))
+
+	//Output:
+	// foo, bar
+}
+
+func ExampleTokenizer() {
+	tokenizer := sfv.NewDictionaryTokenizer([]string{`a=(1 "x");y, b`})
+	for {
+		ev, err := tokenizer.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			panic(err)
+		}
+		if ev.Kind == sfv.EventBareItem {
+			fmt.Println(ev.Kind, ev.ItemKind, ev.Raw)
+		} else {
+			fmt.Println(ev.Kind, ev.Raw)
+		}
+	}
+
+	//Output:
+	// DictKey a
+	// StartInnerList (
+	// BareItem Integer 1
+	// BareItem String "x"
+	// EndInnerList )
+	// ParamKey y
+	// MemberSeparator ,
+	// DictKey b
+}
+
+func ExampleWalk() {
+	dict, err := sfv.DecodeDictionary([]string{`a=(1 2);x, b;y="foo"`})
+	if err != nil {
+		panic(err)
+	}
+	err = sfv.Walk(dict, func(node sfv.Node) error {
+		fmt.Printf("%s %q\n", node.Kind, node.Path)
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	//Output:
+	// Dictionary ""
+	// InnerList "a"
+	// Item "a[0]"
+	// Item "a[1]"
+	// Parameter "a;x"
+	// Item "b"
+	// Parameter "b;y"
+}
+
+func ExampleSelect() {
+	dict, err := sfv.DecodeDictionary([]string{`a=(1 2);x=?0, b=3;x=4`})
+	if err != nil {
+		panic(err)
+	}
+	nodes, err := sfv.Select(dict, "[*];x")
+	if err != nil {
+		panic(err)
+	}
+	for _, node := range nodes {
+		s, err := node.Encode()
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(node.Path, s)
+	}
+
+	//Output:
+	// a;x ?0
+	// b;x 4
+}
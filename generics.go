@@ -0,0 +1,81 @@
+//go:build go1.21
+
+package sfv
+
+import (
+	"fmt"
+	"time"
+)
+
+// ValueType is the set of the types of the decoded values.
+// See Value for the mapping between them and the types of Structured Field Values.
+//
+// The generic helpers require Go 1.21 or later.
+// go.mod declares go 1.17, and older toolchains compile this package with the Go 1.17 language version.
+type ValueType interface {
+	int64 | float64 | string | Token | []byte | bool | time.Time | DisplayString | InnerList
+}
+
+// ValueAs returns the value of item as T.
+// It returns an error if the value is not T.
+func ValueAs[T ValueType](item Item) (T, error) {
+	return valueAs[T]("item", item.Value)
+}
+
+// ParamAs returns the value of the parameter associated with key as T.
+// It returns an error if the parameter is not found, or its value is not T.
+func ParamAs[T ValueType](params Parameters, key string) (T, error) {
+	v := params.Get(key)
+	if v == nil {
+		var zero T
+		return zero, fmt.Errorf("sfv: parameter %q is not found", key)
+	}
+	return valueAs[T](fmt.Sprintf("parameter %q", key), v)
+}
+
+// DictAs returns the value of the dictionary member associated with key as T.
+// The parameters of the member are ignored.
+// It returns an error if the member is not found, or its value is not T.
+func DictAs[T ValueType](dict Dictionary, key string) (T, error) {
+	item := dict.Get(key)
+	if item.Value == nil {
+		var zero T
+		return zero, fmt.Errorf("sfv: dictionary member %q is not found", key)
+	}
+	return valueAs[T](fmt.Sprintf("dictionary member %q", key), item.Value)
+}
+
+func valueAs[T ValueType](name string, v Value) (T, error) {
+	ret, ok := v.(T)
+	if !ok {
+		return ret, fmt.Errorf("sfv: %s is %s, not %s", name, typeName(v), typeName(ret))
+	}
+	return ret, nil
+}
+
+// typeName returns the name of the type of Structured Field Values.
+func typeName(v Value) string {
+	switch v.(type) {
+	case int64:
+		return "an integer"
+	case float64:
+		return "a decimal"
+	case string:
+		return "a string"
+	case Token:
+		return "a token"
+	case []byte:
+		return "a byte sequence"
+	case bool:
+		return "a boolean"
+	case time.Time:
+		return "a date"
+	case DisplayString:
+		return "a display string"
+	case InnerList:
+		return "an inner list"
+	case nil:
+		return "nil"
+	}
+	return fmt.Sprintf("%T", v)
+}
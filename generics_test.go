@@ -0,0 +1,91 @@
+//go:build go1.21
+
+package sfv
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValueAs(t *testing.T) {
+	item, err := DecodeItem([]string{`@1659578233;a=1;b=2.5;c="foo";d=bar;e=:AQID:;f;g=%"caf%c3%a9"`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	date, err := ValueAs[time.Time](item)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !date.Equal(time.Unix(1659578233, 0)) {
+		t.Errorf("want %v, got %v", time.Unix(1659578233, 0), date)
+	}
+	if _, err := ValueAs[int64](item); err == nil {
+		t.Error("want error, but not")
+	} else if got, want := err.Error(), "sfv: item is a date, not an integer"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	if v, err := ParamAs[int64](item.Parameters, "a"); err != nil || v != 1 {
+		t.Errorf("want 1, got %v, %v", v, err)
+	}
+	if v, err := ParamAs[float64](item.Parameters, "b"); err != nil || v != 2.5 {
+		t.Errorf("want 2.5, got %v, %v", v, err)
+	}
+	if v, err := ParamAs[string](item.Parameters, "c"); err != nil || v != "foo" {
+		t.Errorf("want foo, got %v, %v", v, err)
+	}
+	if v, err := ParamAs[Token](item.Parameters, "d"); err != nil || v != "bar" {
+		t.Errorf("want bar, got %v, %v", v, err)
+	}
+	if v, err := ParamAs[[]byte](item.Parameters, "e"); err != nil || !reflect.DeepEqual(v, []byte{1, 2, 3}) {
+		t.Errorf("want [1 2 3], got %v, %v", v, err)
+	}
+	if v, err := ParamAs[bool](item.Parameters, "f"); err != nil || !v {
+		t.Errorf("want true, got %v, %v", v, err)
+	}
+	if v, err := ParamAs[DisplayString](item.Parameters, "g"); err != nil || v != "café" {
+		t.Errorf("want café, got %v, %v", v, err)
+	}
+
+	if _, err := ParamAs[string](item.Parameters, "d"); err == nil {
+		t.Error("want error, but not")
+	} else if got, want := err.Error(), `sfv: parameter "d" is a token, not a string`; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	if _, err := ParamAs[string](item.Parameters, "missing"); err == nil {
+		t.Error("want error, but not")
+	} else if got, want := err.Error(), `sfv: parameter "missing" is not found`; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestDictAs(t *testing.T) {
+	dict, err := DecodeDictionary([]string{`a=(1 2), b=3;x`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := DictAs[InnerList](dict, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (InnerList{{Value: int64(1)}, {Value: int64(2)}}); !reflect.DeepEqual(list, want) {
+		t.Errorf("want %v, got %v", want, list)
+	}
+	if v, err := DictAs[int64](dict, "b"); err != nil || v != 3 {
+		t.Errorf("want 3, got %v, %v", v, err)
+	}
+
+	if _, err := DictAs[int64](dict, "a"); err == nil {
+		t.Error("want error, but not")
+	} else if got, want := err.Error(), `sfv: dictionary member "a" is an inner list, not an integer`; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	if _, err := DictAs[int64](dict, "missing"); err == nil {
+		t.Error("want error, but not")
+	} else if got, want := err.Error(), `sfv: dictionary member "missing" is not found`; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
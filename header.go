@@ -0,0 +1,179 @@
+package sfv
+
+import (
+	"net/http"
+	"net/textproto"
+)
+
+// Header is a set of HTTP header fields.
+// It is implemented by http.Header and textproto.MIMEHeader.
+type Header interface {
+	Values(key string) []string
+	Set(key, value string)
+	Add(key, value string)
+	Del(key string)
+}
+
+var (
+	_ Header = http.Header(nil)
+	_ Header = textproto.MIMEHeader(nil)
+)
+
+// trailerHeader is a Header that accesses the trailers of a response
+// using the http.TrailerPrefix convention.
+type trailerHeader http.Header
+
+// TrailerHeader returns a Header that reads and writes trailers in h.
+// The keys are canonicalized and prefixed with http.TrailerPrefix,
+// so h is typically the header map of an http.ResponseWriter.
+//
+// The trailers of received messages (http.Request.Trailer and http.Response.Trailer)
+// are plain http.Header values and don't need this wrapper.
+func TrailerHeader(h http.Header) Header {
+	return trailerHeader(h)
+}
+
+func trailerKey(key string) string {
+	return http.TrailerPrefix + textproto.CanonicalMIMEHeaderKey(key)
+}
+
+func (h trailerHeader) Values(key string) []string {
+	return h[trailerKey(key)]
+}
+
+func (h trailerHeader) Set(key, value string) {
+	h[trailerKey(key)] = []string{value}
+}
+
+func (h trailerHeader) Add(key, value string) {
+	key = trailerKey(key)
+	h[key] = append(h[key], value)
+}
+
+func (h trailerHeader) Del(key string) {
+	delete(h, trailerKey(key))
+}
+
+// GetItem decodes the field name in h as an Item.
+func GetItem(h Header, name string) (Item, error) {
+	return DecodeItem(h.Values(name))
+}
+
+// GetList decodes the field name in h as a List.
+// It returns an empty list if h doesn't contain the field.
+func GetList(h Header, name string) (List, error) {
+	return DecodeList(h.Values(name))
+}
+
+// GetDictionary decodes the field name in h as a Dictionary.
+// It returns an empty dictionary if h doesn't contain the field.
+func GetDictionary(h Header, name string) (Dictionary, error) {
+	return DecodeDictionary(h.Values(name))
+}
+
+// SetItem encodes item and sets it to the field name in h.
+// It replaces any existing values of the field.
+func SetItem(h Header, name string, item Item) error {
+	val, err := EncodeItem(item)
+	if err != nil {
+		return err
+	}
+	h.Set(name, val)
+	return nil
+}
+
+// SetList encodes list and sets it to the field name in h.
+// It replaces any existing values of the field.
+// If list is empty, the field is deleted.
+func SetList(h Header, name string, list List) error {
+	val, err := EncodeList(list)
+	if err != nil {
+		return err
+	}
+	setField(h, name, val)
+	return nil
+}
+
+// SetDictionary encodes dict and sets it to the field name in h.
+// It replaces any existing values of the field.
+// If dict is empty, the field is deleted.
+func SetDictionary(h Header, name string, dict Dictionary) error {
+	val, err := EncodeDictionary(dict)
+	if err != nil {
+		return err
+	}
+	setField(h, name, val)
+	return nil
+}
+
+// AddItem encodes item and adds it to the field name in h as a new member of a List.
+// It appends a new field line, so the existing members are kept.
+func AddItem(h Header, name string, item Item) error {
+	return AddList(h, name, List{item})
+}
+
+// AddList encodes list and adds it to the field name in h.
+// It appends a new field line, so the existing members are kept.
+// If list is empty, h is not modified.
+func AddList(h Header, name string, list List) error {
+	val, err := EncodeList(list)
+	if err != nil {
+		return err
+	}
+	if val != "" {
+		h.Add(name, val)
+	}
+	return nil
+}
+
+// AddDictionary encodes dict and adds it to the field name in h.
+// It appends a new field line, so the existing members are kept
+// unless dict contains the same keys.
+// If dict is empty, h is not modified.
+func AddDictionary(h Header, name string, dict Dictionary) error {
+	val, err := EncodeDictionary(dict)
+	if err != nil {
+		return err
+	}
+	if val != "" {
+		h.Add(name, val)
+	}
+	return nil
+}
+
+// SetListLines is similar to SetList, but splits list into multiple field lines
+// in the same way as EncodeListLines.
+func SetListLines(h Header, name string, list List, maxLen int) error {
+	lines, err := EncodeListLines(list, maxLen)
+	if err != nil {
+		return err
+	}
+	setLines(h, name, lines)
+	return nil
+}
+
+// SetDictionaryLines is similar to SetDictionary, but splits dict into multiple field lines
+// in the same way as EncodeDictionaryLines.
+func SetDictionaryLines(h Header, name string, dict Dictionary, maxLen int) error {
+	lines, err := EncodeDictionaryLines(dict, maxLen)
+	if err != nil {
+		return err
+	}
+	setLines(h, name, lines)
+	return nil
+}
+
+func setField(h Header, name, val string) {
+	if val == "" {
+		h.Del(name)
+		return
+	}
+	h.Set(name, val)
+}
+
+func setLines(h Header, name string, lines []string) {
+	h.Del(name)
+	for _, line := range lines {
+		h.Add(name, line)
+	}
+}
@@ -0,0 +1,175 @@
+package sfv
+
+import (
+	"net/http"
+	"net/textproto"
+	"reflect"
+	"testing"
+)
+
+func TestGetDictionary(t *testing.T) {
+	h := make(http.Header)
+	h.Add("example-dict", "a=1")
+	h.Add("Example-Dict", "b=2")
+
+	dict, err := GetDictionary(h, "EXAMPLE-DICT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Dictionary{
+		{Key: "a", Item: Item{Value: int64(1)}},
+		{Key: "b", Item: Item{Value: int64(2)}},
+	}
+	if !reflect.DeepEqual(dict, want) {
+		t.Errorf("want %v, got %v", want, dict)
+	}
+}
+
+func TestSetList(t *testing.T) {
+	h := make(http.Header)
+	h.Add("Example-List", "foo")
+
+	list := List{
+		{Value: Token("bar")},
+		{Value: Token("baz")},
+	}
+	if err := SetList(h, "example-list", list); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := h["Example-List"], []string{"bar, baz"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	// setting an empty list deletes the field.
+	if err := SetList(h, "example-list", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := h["Example-List"]; ok {
+		t.Error("want the field to be deleted")
+	}
+}
+
+func TestSetItem(t *testing.T) {
+	h := make(textproto.MIMEHeader)
+	if err := SetItem(h, "example-item", Item{Value: int64(42)}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := h["Example-Item"], []string{"42"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	item, err := GetItem(h, "Example-Item")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Value != int64(42) {
+		t.Errorf("want 42, got %v", item.Value)
+	}
+}
+
+func TestAddItem(t *testing.T) {
+	h := make(http.Header)
+	if err := AddItem(h, "Example-List", Item{Value: Token("foo")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddItem(h, "Example-List", Item{Value: Token("bar")}); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := GetList(h, "Example-List")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := List{
+		{Value: Token("foo")},
+		{Value: Token("bar")},
+	}
+	if !reflect.DeepEqual(list, want) {
+		t.Errorf("want %v, got %v", want, list)
+	}
+}
+
+func TestAddDictionary(t *testing.T) {
+	h := make(http.Header)
+	if err := AddDictionary(h, "Example-Dict", Dictionary{{Key: "a", Item: Item{Value: int64(1)}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddDictionary(h, "Example-Dict", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddDictionary(h, "Example-Dict", Dictionary{{Key: "b", Item: Item{Value: int64(2)}}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := h["Example-Dict"], []string{"a=1", "b=2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestSetListLines(t *testing.T) {
+	h := make(http.Header)
+	list := List{
+		{Value: Token("foo")},
+		{Value: Token("bar")},
+		{Value: Token("baz")},
+	}
+	if err := SetListLines(h, "Example-List", list, 8); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := h["Example-List"], []string{"foo, bar", "baz"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	if err := SetListLines(h, "Example-List", list, 0); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := h["Example-List"], []string{"foo", "bar", "baz"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestSetDictionaryLines(t *testing.T) {
+	h := make(http.Header)
+	dict := Dictionary{
+		{Key: "a", Item: Item{Value: int64(1)}},
+		{Key: "b", Item: Item{Value: true}},
+		{Key: "c", Item: Item{Value: "foo"}},
+	}
+	if err := SetDictionaryLines(h, "Example-Dict", dict, 7); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := h["Example-Dict"], []string{"a=1, b", `c="foo"`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	// a member is longer than the limit.
+	if err := SetDictionaryLines(h, "Example-Dict", dict, 4); err == nil {
+		t.Error("want error, but not")
+	}
+}
+
+func TestTrailerHeader(t *testing.T) {
+	h := make(http.Header)
+	trailer := TrailerHeader(h)
+	if err := SetList(trailer, "example-list", List{{Value: Token("foo")}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddItem(trailer, "example-list", Item{Value: Token("bar")}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := h[http.TrailerPrefix+"Example-List"], []string{"foo", "bar"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	list, err := GetList(trailer, "Example-List")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 {
+		t.Errorf("want 2 members, got %d", len(list))
+	}
+
+	trailer.Del("example-list")
+	if len(h) != 0 {
+		t.Errorf("want no trailers, got %v", h)
+	}
+}
@@ -0,0 +1,365 @@
+package httpsig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/shogo82148/go-sfv"
+)
+
+// FieldType is the top-level type of a structured field.
+// It is required to re-serialize the field for the sf parameter.
+type FieldType int
+
+const (
+	// FieldTypeUnknown means that the field is not a known structured field.
+	FieldTypeUnknown FieldType = iota
+
+	// FieldTypeItem is an Item.
+	FieldTypeItem
+
+	// FieldTypeList is a List.
+	FieldTypeList
+
+	// FieldTypeDictionary is a Dictionary.
+	FieldTypeDictionary
+)
+
+// knownFieldTypes is the types of the well-known structured fields.
+var knownFieldTypes = map[string]FieldType{
+	"accept-ch":           FieldTypeList,
+	"accept-signature":    FieldTypeDictionary,
+	"cache-status":        FieldTypeList,
+	"content-digest":      FieldTypeDictionary,
+	"document-policy":     FieldTypeDictionary,
+	"permissions-policy":  FieldTypeDictionary,
+	"priority":            FieldTypeDictionary,
+	"proxy-status":        FieldTypeList,
+	"repr-digest":         FieldTypeDictionary,
+	"signature":           FieldTypeDictionary,
+	"signature-input":     FieldTypeDictionary,
+	"want-content-digest": FieldTypeDictionary,
+	"want-repr-digest":    FieldTypeDictionary,
+}
+
+// Builder builds the signature base defined in RFC 9421 Section 2.5.
+// The zero value is ready to use.
+type Builder struct {
+	// FieldTypes maps the lowercased field names to their types.
+	// It is used by the sf parameter.
+	// The types of the well-known structured fields, e.g. priority and signature-input, are used
+	// if a field is not found in FieldTypes.
+	FieldTypes map[string]FieldType
+}
+
+// RequestBase returns the signature base of req using the zero Builder.
+func RequestBase(req *http.Request, params sfv.Item) ([]byte, error) {
+	var b Builder
+	return b.RequestBase(req, params)
+}
+
+// ResponseBase returns the signature base of resp using the zero Builder.
+func ResponseBase(resp *http.Response, params sfv.Item) ([]byte, error) {
+	var b Builder
+	return b.ResponseBase(resp, params)
+}
+
+// RequestBase returns the signature base of req.
+// params is the signature parameters, that is the result of SignatureParams.Item when signing,
+// or the member of the Signature-Input field when verifying.
+// It is used as is, so the order of the parameters is kept.
+func (b *Builder) RequestBase(req *http.Request, params sfv.Item) ([]byte, error) {
+	if req == nil {
+		return nil, errors.New("httpsig: the request is nil")
+	}
+	return b.base(req, nil, params)
+}
+
+// ResponseBase returns the signature base of resp.
+// The components with the req parameter are derived from resp.Request.
+// See RequestBase for params.
+func (b *Builder) ResponseBase(resp *http.Response, params sfv.Item) ([]byte, error) {
+	if resp == nil {
+		return nil, errors.New("httpsig: the response is nil")
+	}
+	return b.base(resp.Request, resp, params)
+}
+
+func (b *Builder) base(req *http.Request, resp *http.Response, params sfv.Item) ([]byte, error) {
+	p, err := ParseSignatureParams(params)
+	if err != nil {
+		return nil, err
+	}
+	sigParams, err := sfv.EncodeList(sfv.List{params})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for _, c := range p.Components {
+		value, err := b.componentValue(req, resp, c)
+		if err != nil {
+			return nil, err
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, fmt.Errorf("httpsig: the value of %s contains a newline", c)
+		}
+		buf.WriteString(c.String())
+		buf.WriteString(": ")
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+	}
+	buf.WriteString(`"` + ComponentSignatureParams + `": `)
+	buf.WriteString(sigParams)
+	return buf.Bytes(), nil
+}
+
+// componentValue returns the component value defined in RFC 9421 Section 2.1 and 2.2.
+// resp is nil if the target message is a request.
+func (b *Builder) componentValue(req *http.Request, resp *http.Response, c Component) (string, error) {
+	if c.Parameters.Get("req") != nil {
+		if resp == nil {
+			return "", fmt.Errorf("httpsig: %s is only allowed in responses", c)
+		}
+		resp = nil
+	}
+	if resp == nil && req == nil {
+		return "", fmt.Errorf("httpsig: %s requires the request", c)
+	}
+
+	if c.IsDerived() {
+		return derivedValue(req, resp, c)
+	}
+
+	var values []string
+	tr := c.Parameters.Get("tr") != nil
+	switch {
+	case resp != nil && tr:
+		values = resp.Trailer.Values(c.Name)
+	case resp != nil:
+		values = resp.Header.Values(c.Name)
+	case tr:
+		values = req.Trailer.Values(c.Name)
+	case c.Name == "host":
+		// net/http moves the Host header field into req.Host.
+		if host := requestHost(req); host != "" {
+			values = []string{host}
+		}
+	default:
+		values = req.Header.Values(c.Name)
+	}
+	if len(values) == 0 {
+		return "", fmt.Errorf("httpsig: %s is not found", c)
+	}
+	return b.fieldValue(values, c)
+}
+
+// fieldValue returns the value of the HTTP field defined in RFC 9421 Section 2.1.
+func (b *Builder) fieldValue(values []string, c Component) (string, error) {
+	trimmed := make([]string, 0, len(values))
+	for _, v := range values {
+		trimmed = append(trimmed, strings.Trim(v, " \t"))
+	}
+
+	if c.Parameters.Get("bs") != nil {
+		for i, v := range trimmed {
+			s, err := sfv.EncodeItem(sfv.Item{Value: []byte(v)})
+			if err != nil {
+				return "", err
+			}
+			trimmed[i] = s
+		}
+		return strings.Join(trimmed, ", "), nil
+	}
+
+	if key, ok := c.Parameters.Get("key").(string); ok {
+		item, found, err := sfv.DecodeOptions{ValidateSkipped: true}.LookupDictionaryMember(trimmed, key)
+		if err != nil {
+			return "", fmt.Errorf("httpsig: failed to parse %s: %w", c, err)
+		}
+		if !found {
+			return "", fmt.Errorf("httpsig: %s is not found", c)
+		}
+		// a single member list is serialized as same as the member value.
+		return sfv.EncodeList(sfv.List{item})
+	}
+
+	if c.Parameters.Get("sf") != nil {
+		switch b.fieldType(c.Name) {
+		case FieldTypeItem:
+			item, err := sfv.DecodeItem(trimmed)
+			if err != nil {
+				return "", fmt.Errorf("httpsig: failed to parse %s: %w", c, err)
+			}
+			return sfv.EncodeItem(item)
+		case FieldTypeList:
+			list, err := sfv.DecodeList(trimmed)
+			if err != nil {
+				return "", fmt.Errorf("httpsig: failed to parse %s: %w", c, err)
+			}
+			return sfv.EncodeList(list)
+		case FieldTypeDictionary:
+			dict, err := sfv.DecodeDictionary(trimmed)
+			if err != nil {
+				return "", fmt.Errorf("httpsig: failed to parse %s: %w", c, err)
+			}
+			return sfv.EncodeDictionary(dict)
+		default:
+			return "", fmt.Errorf("httpsig: the type of %q is unknown", c.Name)
+		}
+	}
+
+	return strings.Join(trimmed, ", "), nil
+}
+
+func (b *Builder) fieldType(name string) FieldType {
+	if t, ok := b.FieldTypes[name]; ok {
+		return t
+	}
+	return knownFieldTypes[name]
+}
+
+// derivedValue returns the value of the derived component defined in RFC 9421 Section 2.2.
+func derivedValue(req *http.Request, resp *http.Response, c Component) (string, error) {
+	if c.Name == ComponentStatus {
+		if resp == nil {
+			return "", fmt.Errorf("httpsig: %s is only allowed in responses", c)
+		}
+		if resp.StatusCode < 100 || resp.StatusCode > 999 {
+			return "", fmt.Errorf("httpsig: invalid status code %d", resp.StatusCode)
+		}
+		return strconv.Itoa(resp.StatusCode), nil
+	}
+
+	if resp != nil {
+		return "", fmt.Errorf("httpsig: %s is only allowed in requests", c)
+	}
+	if req.URL == nil {
+		return "", errors.New("httpsig: the request URL is nil")
+	}
+	switch c.Name {
+	case ComponentMethod:
+		if req.Method == "" {
+			return http.MethodGet, nil
+		}
+		return req.Method, nil
+	case ComponentTargetURI:
+		return requestScheme(req) + "://" + requestAuthority(req) + req.URL.RequestURI(), nil
+	case ComponentAuthority:
+		return requestAuthority(req), nil
+	case ComponentScheme:
+		return requestScheme(req), nil
+	case ComponentRequestTarget:
+		if req.RequestURI != "" {
+			return req.RequestURI, nil
+		}
+		return req.URL.RequestURI(), nil
+	case ComponentPath:
+		if path := req.URL.EscapedPath(); path != "" {
+			return path, nil
+		}
+		return "/", nil
+	case ComponentQuery:
+		return "?" + req.URL.RawQuery, nil
+	case ComponentQueryParam:
+		return queryParam(req.URL.RawQuery, c)
+	}
+	return "", fmt.Errorf("httpsig: unknown derived component %q", c.Name)
+}
+
+func requestHost(req *http.Request) string {
+	if req.Host != "" {
+		return req.Host
+	}
+	if req.URL != nil {
+		return req.URL.Host
+	}
+	return ""
+}
+
+// requestScheme returns the lowercased scheme of req.
+func requestScheme(req *http.Request) string {
+	if req.URL.Scheme != "" {
+		return strings.ToLower(req.URL.Scheme)
+	}
+	if req.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// requestAuthority returns the authority of req normalized according to RFC 9110 Section 4.2.3.
+func requestAuthority(req *http.Request) string {
+	host := strings.ToLower(requestHost(req))
+	switch requestScheme(req) {
+	case "http":
+		host = strings.TrimSuffix(host, ":80")
+	case "https":
+		host = strings.TrimSuffix(host, ":443")
+	}
+	return host
+}
+
+// queryParam returns the value of the @query-param component defined in RFC 9421 Section 2.2.8.
+func queryParam(rawQuery string, c Component) (string, error) {
+	name, _ := c.Parameters.Get("name").(string)
+	want, err := url.QueryUnescape(name)
+	if err != nil {
+		return "", fmt.Errorf("httpsig: invalid name parameter of %s: %w", c, err)
+	}
+
+	var value string
+	var found bool
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		k, v := pair, ""
+		if i := strings.IndexByte(pair, '='); i >= 0 {
+			k, v = pair[:i], pair[i+1:]
+		}
+		k, err := url.QueryUnescape(k)
+		if err != nil {
+			return "", fmt.Errorf("httpsig: invalid query: %w", err)
+		}
+		if k != want {
+			continue
+		}
+		if found {
+			return "", fmt.Errorf("httpsig: %s occurs multiple times", c)
+		}
+		value, err = url.QueryUnescape(v)
+		if err != nil {
+			return "", fmt.Errorf("httpsig: invalid query: %w", err)
+		}
+		found = true
+	}
+	if !found {
+		return "", fmt.Errorf("httpsig: %s is not found", c)
+	}
+	return percentEncode(value), nil
+}
+
+// percentEncode encodes s using the application/x-www-form-urlencoded percent-encode set,
+// but the spaces are encoded as "%20" instead of "+".
+func percentEncode(s string) string {
+	const upperhex = "0123456789ABCDEF"
+	var buf strings.Builder
+	for _, ch := range []byte(s) {
+		switch {
+		case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z', ch >= '0' && ch <= '9',
+			ch == '*', ch == '-', ch == '.', ch == '_':
+			buf.WriteByte(ch)
+		default:
+			buf.WriteByte('%')
+			buf.WriteByte(upperhex[ch>>4])
+			buf.WriteByte(upperhex[ch&0x0f])
+		}
+	}
+	return buf.String()
+}
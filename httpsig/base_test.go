@@ -0,0 +1,272 @@
+package httpsig
+
+import (
+	"bufio"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/shogo82148/go-sfv"
+)
+
+// the test request in RFC 9421 Appendix B.2.
+const testRequest = "POST /foo?param=Value&Pet=dog HTTP/1.1\r\n" +
+	"Host: example.com\r\n" +
+	"Date: Tue, 20 Apr 2021 02:07:55 GMT\r\n" +
+	"Content-Type: application/json\r\n" +
+	"Content-Digest: sha-512=:WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyealdVLvRwEmTHWXvJwew==:\r\n" +
+	"Content-Length: 18\r\n" +
+	"\r\n" +
+	`{"hello": "world"}`
+
+// the test response in RFC 9421 Appendix B.2.
+const testResponse = "HTTP/1.1 200 OK\r\n" +
+	"Date: Tue, 20 Apr 2021 02:07:56 GMT\r\n" +
+	"Content-Type: application/json\r\n" +
+	"Content-Digest: sha-512=:mEWXIS7MaLRuGgxOBdODa3xqM1XdEvxoYhvlCFJ41QJgJc4GTsPp29l5oGX69wWdXymyU0rjJuahq4l5aGgfLQ==:\r\n" +
+	"Content-Length: 23\r\n" +
+	"\r\n" +
+	`{"message": "good dog"}`
+
+func readRequest(t *testing.T, raw string) *http.Request {
+	t.Helper()
+	req, err := http.ReadRequest(bufio.NewReader(strings.NewReader(raw)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func readResponse(t *testing.T, raw string, req *http.Request) *http.Response {
+	t.Helper()
+	resp, err := http.ReadResponse(bufio.NewReader(strings.NewReader(raw)), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func decodeSignatureParams(t *testing.T, s string) sfv.Item {
+	t.Helper()
+	list, err := sfv.DecodeList([]string{s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("want 1 member, got %d", len(list))
+	}
+	return list[0]
+}
+
+func TestRequestBase(t *testing.T) {
+	tests := []struct {
+		name   string
+		params string
+		want   string
+	}{
+		{
+			name:   "B.2.1 Minimal Signature Using rsa-pss-sha512",
+			params: `();created=1618884473;keyid="test-key-rsa-pss";nonce="b3k2pp5k7z-50gnwp.yemd"`,
+			want:   `"@signature-params": ();created=1618884473;keyid="test-key-rsa-pss";nonce="b3k2pp5k7z-50gnwp.yemd"`,
+		},
+		{
+			name:   "B.2.2 Selective Covered Components Using rsa-pss-sha512",
+			params: `("@authority" "content-digest" "@query-param";name="Pet");created=1618884473;keyid="test-key-rsa-pss";tag="header-example"`,
+			want: `"@authority": example.com` + "\n" +
+				`"content-digest": sha-512=:WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyealdVLvRwEmTHWXvJwew==:` + "\n" +
+				`"@query-param";name="Pet": dog` + "\n" +
+				`"@signature-params": ("@authority" "content-digest" "@query-param";name="Pet");created=1618884473;keyid="test-key-rsa-pss";tag="header-example"`,
+		},
+		{
+			name:   "B.2.3 Full Coverage Using rsa-pss-sha512",
+			params: `("date" "@method" "@path" "@query" "@authority" "content-type" "content-digest" "content-length");created=1618884473;keyid="test-key-rsa-pss"`,
+			want: `"date": Tue, 20 Apr 2021 02:07:55 GMT` + "\n" +
+				`"@method": POST` + "\n" +
+				`"@path": /foo` + "\n" +
+				`"@query": ?param=Value&Pet=dog` + "\n" +
+				`"@authority": example.com` + "\n" +
+				`"content-type": application/json` + "\n" +
+				`"content-digest": sha-512=:WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyealdVLvRwEmTHWXvJwew==:` + "\n" +
+				`"content-length": 18` + "\n" +
+				`"@signature-params": ("date" "@method" "@path" "@query" "@authority" "content-type" "content-digest" "content-length");created=1618884473;keyid="test-key-rsa-pss"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := readRequest(t, testRequest)
+			got, err := RequestBase(req, decodeSignatureParams(t, tt.params))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("want:\n%s\ngot:\n%s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestResponseBase(t *testing.T) {
+	req := readRequest(t, testRequest)
+	resp := readResponse(t, testResponse, req)
+
+	// B.2.4 Signing a Response Using ecdsa-p256-sha256
+	params := `("@status" "content-type" "content-digest" "content-length");created=1618884473;keyid="test-key-ecc-p256"`
+	want := `"@status": 200` + "\n" +
+		`"content-type": application/json` + "\n" +
+		`"content-digest": sha-512=:mEWXIS7MaLRuGgxOBdODa3xqM1XdEvxoYhvlCFJ41QJgJc4GTsPp29l5oGX69wWdXymyU0rjJuahq4l5aGgfLQ==:` + "\n" +
+		`"content-length": 23` + "\n" +
+		`"@signature-params": ("@status" "content-type" "content-digest" "content-length");created=1618884473;keyid="test-key-ecc-p256"`
+	got, err := ResponseBase(resp, decodeSignatureParams(t, params))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("want:\n%s\ngot:\n%s", want, got)
+	}
+
+	// RFC 9421 Section 2.4. Signing Request Components in a Response Message
+	params = `("@status" "content-type" "@authority";req "@method";req "@path";req "content-digest";req);created=1618884479;keyid="test-key-ecc-p256"`
+	want = `"@status": 200` + "\n" +
+		`"content-type": application/json` + "\n" +
+		`"@authority";req: example.com` + "\n" +
+		`"@method";req: POST` + "\n" +
+		`"@path";req: /foo` + "\n" +
+		`"content-digest";req: sha-512=:WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyealdVLvRwEmTHWXvJwew==:` + "\n" +
+		`"@signature-params": ("@status" "content-type" "@authority";req "@method";req "@path";req "content-digest";req);created=1618884479;keyid="test-key-ecc-p256"`
+	got, err = ResponseBase(resp, decodeSignatureParams(t, params))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("want:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestBuilder_componentValue(t *testing.T) {
+	// RFC 9421 Section 2.1. HTTP Fields
+	fields := "GET /path HTTP/1.1\r\n" +
+		"Host: www.example.com\r\n" +
+		"Date: Tue, 20 Apr 2021 02:07:56 GMT\r\n" +
+		"X-OWS-Header:   Leading and trailing whitespace.   \r\n" +
+		"X-Obs-Fold-Header: Obsolete\r\n" +
+		"    line folding.\r\n" +
+		"Cache-Control: max-age=60\r\n" +
+		"Cache-Control:    must-revalidate\r\n" +
+		"Example-Dict:  a=1,    b=2;x=1;y=2,   c=(a b c)\r\n" +
+		"Example-Dict:  d\r\n" +
+		"Example-Header: value, with, lots\r\n" +
+		"Example-Header: of, commas\r\n" +
+		"Example-List: a,   (b c);x\r\n" +
+		"\r\n"
+
+	// RFC 9421 Section 2.2. Derived Components
+	derived := "POST /path?param=value&foo=bar&baz=batman&qux=&var=this%20is%20a%20big%0Avalue&bar=with+plus+whitespace&fa%C3%A7ade%22%3A%20=something&dup=1&dup=2 HTTP/1.1\r\n" +
+		"Host: www.EXAMPLE.com:443\r\n" +
+		"\r\n"
+
+	tests := []struct {
+		raw       string
+		component string
+		want      string
+	}{
+		{fields, `"host"`, "www.example.com"},
+		{fields, `"date"`, "Tue, 20 Apr 2021 02:07:56 GMT"},
+		{fields, `"x-ows-header"`, "Leading and trailing whitespace."},
+		{fields, `"x-obs-fold-header"`, "Obsolete line folding."},
+		{fields, `"cache-control"`, "max-age=60, must-revalidate"},
+		{fields, `"example-dict"`, "a=1,    b=2;x=1;y=2,   c=(a b c), d"},
+		{fields, `"example-dict";sf`, "a=1, b=2;x=1;y=2, c=(a b c), d"},
+		{fields, `"example-dict";key="a"`, "1"},
+		{fields, `"example-dict";key="b"`, "2;x=1;y=2"},
+		{fields, `"example-dict";key="c"`, "(a b c)"},
+		{fields, `"example-dict";key="d"`, "?1"},
+		{fields, `"example-header"`, "value, with, lots, of, commas"},
+		{fields, `"example-header";bs`, ":dmFsdWUsIHdpdGgsIGxvdHM=:, :b2YsIGNvbW1hcw==:"},
+		{fields, `"example-list";sf`, "a, (b c);x"},
+
+		{derived, `"@method"`, "POST"},
+		{derived, `"@target-uri"`, "https://www.example.com/path?param=value&foo=bar&baz=batman&qux=&var=this%20is%20a%20big%0Avalue&bar=with+plus+whitespace&fa%C3%A7ade%22%3A%20=something&dup=1&dup=2"},
+		{derived, `"@authority"`, "www.example.com"},
+		{derived, `"@scheme"`, "https"},
+		{derived, `"@request-target"`, "/path?param=value&foo=bar&baz=batman&qux=&var=this%20is%20a%20big%0Avalue&bar=with+plus+whitespace&fa%C3%A7ade%22%3A%20=something&dup=1&dup=2"},
+		{derived, `"@path"`, "/path"},
+		{derived, `"@query-param";name="baz"`, "batman"},
+		{derived, `"@query-param";name="qux"`, ""},
+		{derived, `"@query-param";name="var"`, "this%20is%20a%20big%0Avalue"},
+		{derived, `"@query-param";name="bar"`, "with%20plus%20whitespace"},
+		{derived, `"@query-param";name="fa%C3%A7ade%22%3A%20"`, "something"},
+	}
+
+	b := &Builder{
+		FieldTypes: map[string]FieldType{
+			"example-dict": FieldTypeDictionary,
+			"example-list": FieldTypeList,
+		},
+	}
+	for _, tt := range tests {
+		req := readRequest(t, tt.raw)
+		req.URL.Scheme = "https"
+		c, err := ParseComponent(tt.component)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := b.componentValue(req, nil, c)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.component, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: want %q, got %q", tt.component, tt.want, got)
+		}
+	}
+
+	invalid := []struct {
+		raw       string
+		component string
+	}{
+		{fields, `"not-found"`},
+		{fields, `"example-dict";key="e"`},
+		{fields, `"example-header";sf`},
+		{fields, `"x-ows-header";key="a"`},
+		{fields, `"@status"`},
+		{fields, `"@method";req`},
+		{derived, `"@query-param";name="dup"`},
+		{derived, `"@query-param";name="not-found"`},
+	}
+	for _, tt := range invalid {
+		req := readRequest(t, tt.raw)
+		c, err := ParseComponent(tt.component)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := b.componentValue(req, nil, c); err == nil {
+			t.Errorf("%s: want error, but not", tt.component)
+		}
+	}
+}
+
+func TestDerivedValue_Query(t *testing.T) {
+	for _, tt := range []struct {
+		url  string
+		want string
+	}{
+		{"https://example.com/path?param=value", "?param=value"},
+		{"https://example.com/path?", "?"},
+		{"https://example.com/path", "?"},
+		{"https://example.com", "?"},
+	} {
+		req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := Component{Name: ComponentQuery}
+		got, err := derivedValue(req, nil, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: want %q, got %q", tt.url, tt.want, got)
+		}
+	}
+}
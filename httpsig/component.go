@@ -0,0 +1,174 @@
+package httpsig
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shogo82148/go-sfv"
+)
+
+// The derived component names defined in RFC 9421 Section 2.2.
+const (
+	ComponentMethod        = "@method"
+	ComponentTargetURI     = "@target-uri"
+	ComponentAuthority     = "@authority"
+	ComponentScheme        = "@scheme"
+	ComponentRequestTarget = "@request-target"
+	ComponentPath          = "@path"
+	ComponentQuery         = "@query"
+	ComponentQueryParam    = "@query-param"
+	ComponentStatus        = "@status"
+
+	// ComponentSignatureParams is the name of the signature parameters.
+	// It is not a valid component in the covered components.
+	ComponentSignatureParams = "@signature-params"
+)
+
+// Component is a component identifier defined in RFC 9421 Section 2.
+type Component struct {
+	// Name is the lowercased field name, or the derived component name that starts with "@".
+	Name string
+
+	// Parameters is the parameters of the component identifier, e.g. sf, key, bs, req, tr and name.
+	Parameters sfv.Parameters
+}
+
+// ParseComponent parses the serialized component identifier, e.g. `"@query-param";name="foo"`.
+func ParseComponent(s string) (Component, error) {
+	item, err := sfv.DecodeItem([]string{s})
+	if err != nil {
+		return Component{}, err
+	}
+	c, err := componentFromItem(item)
+	if err != nil {
+		return Component{}, err
+	}
+	if err := c.Validate(); err != nil {
+		return Component{}, err
+	}
+	return c, nil
+}
+
+func componentFromItem(item sfv.Item) (Component, error) {
+	name, ok := item.Value.(string)
+	if !ok {
+		return Component{}, fmt.Errorf("httpsig: the component identifier must be a string, got %T", item.Value)
+	}
+	return Component{
+		Name:       name,
+		Parameters: item.Parameters,
+	}, nil
+}
+
+// Item converts c into the item of the component identifier.
+func (c Component) Item() sfv.Item {
+	return sfv.Item{
+		Value:      c.Name,
+		Parameters: c.Parameters,
+	}
+}
+
+// String returns the serialized component identifier.
+func (c Component) String() string {
+	s, err := sfv.EncodeItem(c.Item())
+	if err != nil {
+		return fmt.Sprintf("%q", c.Name)
+	}
+	return s
+}
+
+// IsDerived reports whether c is a derived component.
+func (c Component) IsDerived() bool {
+	return len(c.Name) > 0 && c.Name[0] == '@'
+}
+
+// Validate checks the syntax of the component identifier according to RFC 9421 Section 2.
+func (c Component) Validate() error {
+	if c.Name == "" {
+		return errors.New("httpsig: the component name is empty")
+	}
+	if c.IsDerived() {
+		switch c.Name {
+		case ComponentMethod, ComponentTargetURI, ComponentAuthority, ComponentScheme,
+			ComponentRequestTarget, ComponentPath, ComponentQuery, ComponentQueryParam, ComponentStatus:
+		case ComponentSignatureParams:
+			return fmt.Errorf("httpsig: %q can't be a covered component", c.Name)
+		default:
+			return fmt.Errorf("httpsig: unknown derived component %q", c.Name)
+		}
+	} else if !isFieldName(c.Name) {
+		return fmt.Errorf("httpsig: invalid field name %q", c.Name)
+	}
+
+	var sf, key, bs, tr, name bool
+	for i, param := range c.Parameters {
+		for _, prev := range c.Parameters[:i] {
+			if prev.Key == param.Key {
+				return fmt.Errorf("httpsig: %q has duplicated parameter %q", c.Name, param.Key)
+			}
+		}
+
+		var ok bool
+		switch param.Key {
+		case "sf":
+			ok = param.Value == true
+			sf = ok
+		case "key":
+			_, ok = param.Value.(string)
+			key = true
+		case "bs":
+			ok = param.Value == true
+			bs = ok
+		case "req":
+			ok = param.Value == true
+		case "tr":
+			ok = param.Value == true
+			tr = ok
+		case "name":
+			_, ok = param.Value.(string)
+			name = true
+		default:
+			return fmt.Errorf("httpsig: %q has unknown parameter %q", c.Name, param.Key)
+		}
+		if !ok {
+			return fmt.Errorf("httpsig: %q has invalid parameter %s=%v", c.Name, param.Key, param.Value)
+		}
+	}
+
+	if c.IsDerived() && (sf || key || bs || tr) {
+		return fmt.Errorf("httpsig: %q is a derived component, but has a parameter for fields", c.Name)
+	}
+	if bs && (sf || key) {
+		return fmt.Errorf("httpsig: %q has both bs and sf or key", c.Name)
+	}
+	if name != (c.Name == ComponentQueryParam) {
+		return fmt.Errorf("httpsig: the name parameter is required for %q, and is not allowed for the others", ComponentQueryParam)
+	}
+	return sfv.ValidateItem(c.Item())
+}
+
+// isFieldName reports whether s is a lowercased field name.
+func isFieldName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, ch := range []byte(s) {
+		if !isTokenChar(ch) || (ch >= 'A' && ch <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// isTokenChar reports whether ch is a tchar defined in RFC 9110 Section 5.6.2.
+func isTokenChar(ch byte) bool {
+	switch {
+	case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z', ch >= '0' && ch <= '9':
+		return true
+	}
+	switch ch {
+	case '!', '#', '$', '%', '&', '\'', '*', '+', '-', '.', '^', '_', '`', '|', '~':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package httpsig
+
+import (
+	"testing"
+)
+
+func TestParseComponent(t *testing.T) {
+	valid := []string{
+		`"@method"`,
+		`"@target-uri"`,
+		`"@authority"`,
+		`"@scheme"`,
+		`"@request-target"`,
+		`"@path"`,
+		`"@query"`,
+		`"@query-param";name="var"`,
+		`"@status"`,
+		`"@method";req`,
+		`"content-type"`,
+		`"example-dict";sf`,
+		`"example-dict";key="a"`,
+		`"example-header";bs`,
+		`"example-trailer";tr`,
+		`"signature";req;key="sig1"`,
+	}
+	for _, s := range valid {
+		c, err := ParseComponent(s)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", s, err)
+			continue
+		}
+		if got := c.String(); got != s {
+			t.Errorf("want %s, got %s", s, got)
+		}
+	}
+
+	invalid := []string{
+		`@method`,
+		`"@unknown"`,
+		`"@signature-params"`,
+		`"Content-Type"`,
+		`"content type"`,
+		`""`,
+		`"@query-param"`,
+		`"@query";name="var"`,
+		`"@method";sf`,
+		`"@path";tr`,
+		`"example-header";bs;sf`,
+		`"example-header";bs;key="a"`,
+		`"example-header";key=a`,
+		`"example-header";req=?0`,
+		`"example-header";unknown`,
+	}
+	for _, s := range invalid {
+		if _, err := ParseComponent(s); err == nil {
+			t.Errorf("%s: want error, but not", s)
+		}
+	}
+}
@@ -0,0 +1,355 @@
+// Package httpsig implements the Signature-Input and Signature fields
+// defined in RFC 9421 HTTP Message Signatures.
+package httpsig
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shogo82148/go-sfv"
+	"github.com/shogo82148/go-sfv/internal/fieldutil"
+)
+
+// The names of the fields.
+const (
+	SignatureInputField = "Signature-Input"
+	SignatureField      = "Signature"
+)
+
+// SignatureParams is the signature parameters defined in RFC 9421 Section 2.3,
+// that is a member of the Signature-Input field.
+type SignatureParams struct {
+	// Components is the ordered list of the covered components.
+	Components []Component
+
+	// Created is the created parameter, or the zero time if it is omitted.
+	Created time.Time
+
+	// Expires is the expires parameter, or the zero time if it is omitted.
+	Expires time.Time
+
+	// Nonce is the nonce parameter, or empty if it is omitted.
+	Nonce string
+
+	// Alg is the alg parameter, or empty if it is omitted.
+	Alg string
+
+	// KeyID is the keyid parameter, or empty if it is omitted.
+	KeyID string
+
+	// Tag is the tag parameter, or empty if it is omitted.
+	Tag string
+
+	// Extra is the parameters that are not defined in RFC 9421.
+	Extra sfv.Parameters
+}
+
+// ParseSignatureParams converts item into the signature parameters.
+func ParseSignatureParams(item sfv.Item) (SignatureParams, error) {
+	list, ok := item.Value.(sfv.InnerList)
+	if !ok {
+		return SignatureParams{}, fmt.Errorf("httpsig: the signature parameters must be an inner list, got %T", item.Value)
+	}
+
+	var p SignatureParams
+	for _, item := range list {
+		c, err := componentFromItem(item)
+		if err != nil {
+			return SignatureParams{}, err
+		}
+		p.Components = append(p.Components, c)
+	}
+
+	for _, param := range item.Parameters {
+		var ok bool
+		switch param.Key {
+		case "created":
+			var v int64
+			v, ok = param.Value.(int64)
+			p.Created = time.Unix(v, 0)
+		case "expires":
+			var v int64
+			v, ok = param.Value.(int64)
+			p.Expires = time.Unix(v, 0)
+		case "nonce":
+			p.Nonce, ok = param.Value.(string)
+		case "alg":
+			p.Alg, ok = param.Value.(string)
+		case "keyid":
+			p.KeyID, ok = param.Value.(string)
+		case "tag":
+			p.Tag, ok = param.Value.(string)
+		default:
+			p.Extra = append(p.Extra, param)
+			ok = true
+		}
+		if !ok {
+			return SignatureParams{}, fmt.Errorf("httpsig: invalid parameter %s=%v", param.Key, param.Value)
+		}
+	}
+
+	if err := p.Validate(); err != nil {
+		return SignatureParams{}, err
+	}
+	return p, nil
+}
+
+// Validate checks that p can be serialized as the signature parameters.
+func (p SignatureParams) Validate() error {
+	for i, c := range p.Components {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+		s := c.String()
+		for _, prev := range p.Components[:i] {
+			if prev.String() == s {
+				return fmt.Errorf("httpsig: the component %s is duplicated", s)
+			}
+		}
+	}
+	if key, ok := fieldutil.ReservedParam(p.Extra, "created", "expires", "nonce", "alg", "keyid", "tag"); ok {
+		return fmt.Errorf("httpsig: the parameter %q is in Extra", key)
+	}
+	return nil
+}
+
+// Item converts p into the member of the Signature-Input field.
+func (p SignatureParams) Item() (sfv.Item, error) {
+	if err := p.Validate(); err != nil {
+		return sfv.Item{}, err
+	}
+
+	list := make(sfv.InnerList, 0, len(p.Components))
+	for _, c := range p.Components {
+		list = append(list, c.Item())
+	}
+	item := sfv.Item{
+		Value: list,
+	}
+	add := func(key string, value sfv.Value) {
+		item.Parameters = append(item.Parameters, sfv.Parameter{Key: key, Value: value})
+	}
+	if !p.Created.IsZero() {
+		add("created", p.Created.Unix())
+	}
+	if !p.Expires.IsZero() {
+		add("expires", p.Expires.Unix())
+	}
+	if p.Nonce != "" {
+		add("nonce", p.Nonce)
+	}
+	if p.Alg != "" {
+		add("alg", p.Alg)
+	}
+	if p.KeyID != "" {
+		add("keyid", p.KeyID)
+	}
+	if p.Tag != "" {
+		add("tag", p.Tag)
+	}
+	item.Parameters = append(item.Parameters, p.Extra...)
+	return item, nil
+}
+
+// Encode serializes p, that is the value of the @signature-params component.
+func (p SignatureParams) Encode() (string, error) {
+	item, err := p.Item()
+	if err != nil {
+		return "", err
+	}
+	return sfv.EncodeList(sfv.List{item})
+}
+
+// Input is a labeled member of the Signature-Input field.
+type Input struct {
+	Label  string
+	Params SignatureParams
+}
+
+// SignatureInput is the Signature-Input field.
+type SignatureInput []Input
+
+// ParseSignatureInput parses the Signature-Input fields.
+func ParseSignatureInput(fields []string) (SignatureInput, error) {
+	dict, err := sfv.DecodeDictionary(fields)
+	if err != nil {
+		return nil, err
+	}
+	if len(dict) == 0 {
+		return nil, nil
+	}
+	si := make(SignatureInput, 0, len(dict))
+	for _, member := range dict {
+		p, err := ParseSignatureParams(member.Item)
+		if err != nil {
+			return nil, fmt.Errorf("httpsig: signature %q: %w", member.Key, err)
+		}
+		si = append(si, Input{Label: member.Key, Params: p})
+	}
+	return si, nil
+}
+
+// Get returns the signature parameters of the label.
+func (si SignatureInput) Get(label string) (SignatureParams, bool) {
+	for _, in := range si {
+		if in.Label == label {
+			return in.Params, true
+		}
+	}
+	return SignatureParams{}, false
+}
+
+// Dictionary converts si into the Signature-Input field.
+func (si SignatureInput) Dictionary() (sfv.Dictionary, error) {
+	dict := make(sfv.Dictionary, 0, len(si))
+	for _, in := range si {
+		item, err := in.Params.Item()
+		if err != nil {
+			return nil, err
+		}
+		dict = append(dict, sfv.DictMember{Key: in.Label, Item: item})
+	}
+	return dict, nil
+}
+
+// Encode serializes si.
+func (si SignatureInput) Encode() (string, error) {
+	dict, err := si.Dictionary()
+	if err != nil {
+		return "", err
+	}
+	return sfv.EncodeDictionary(dict)
+}
+
+// Value is a labeled member of the Signature field.
+type Value struct {
+	Label     string
+	Signature []byte
+}
+
+// Signatures is the Signature field.
+type Signatures []Value
+
+// ParseSignature parses the Signature fields.
+func ParseSignature(fields []string) (Signatures, error) {
+	dict, err := sfv.DecodeDictionary(fields)
+	if err != nil {
+		return nil, err
+	}
+	if len(dict) == 0 {
+		return nil, nil
+	}
+	sigs := make(Signatures, 0, len(dict))
+	for _, member := range dict {
+		sig, ok := member.Item.Value.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("httpsig: signature %q must be a byte sequence, got %T", member.Key, member.Item.Value)
+		}
+		sigs = append(sigs, Value{Label: member.Key, Signature: sig})
+	}
+	return sigs, nil
+}
+
+// Get returns the signature of the label.
+func (sigs Signatures) Get(label string) ([]byte, bool) {
+	for _, v := range sigs {
+		if v.Label == label {
+			return v.Signature, true
+		}
+	}
+	return nil, false
+}
+
+// Dictionary converts sigs into the Signature field.
+func (sigs Signatures) Dictionary() sfv.Dictionary {
+	dict := make(sfv.Dictionary, 0, len(sigs))
+	for _, v := range sigs {
+		dict = append(dict, sfv.DictMember{Key: v.Label, Item: sfv.Item{Value: v.Signature}})
+	}
+	return dict
+}
+
+// Encode serializes sigs.
+func (sigs Signatures) Encode() (string, error) {
+	return sfv.EncodeDictionary(sigs.Dictionary())
+}
+
+// Signature is a signature with its parameters, that are correlated by the label.
+type Signature struct {
+	Label     string
+	Params    SignatureParams
+	Signature []byte
+}
+
+// Correlate pairs the members of the Signature-Input and Signature fields by their labels.
+// The result is in the order of the Signature-Input field.
+// It returns an error if a label is found in only one of them.
+func Correlate(si SignatureInput, sigs Signatures) ([]Signature, error) {
+	ret := make([]Signature, 0, len(si))
+	for _, in := range si {
+		sig, ok := sigs.Get(in.Label)
+		if !ok {
+			return nil, fmt.Errorf("httpsig: signature %q is not found in the Signature field", in.Label)
+		}
+		ret = append(ret, Signature{Label: in.Label, Params: in.Params, Signature: sig})
+	}
+	for _, v := range sigs {
+		if _, ok := si.Get(v.Label); !ok {
+			return nil, fmt.Errorf("httpsig: signature %q is not found in the Signature-Input field", v.Label)
+		}
+	}
+	return ret, nil
+}
+
+// FromHeader parses the Signature-Input and Signature fields in h, and correlates them.
+func FromHeader(h sfv.Header) ([]Signature, error) {
+	si, err := ParseSignatureInput(h.Values(SignatureInputField))
+	if err != nil {
+		return nil, err
+	}
+	sigs, err := ParseSignature(h.Values(SignatureField))
+	if err != nil {
+		return nil, err
+	}
+	return Correlate(si, sigs)
+}
+
+// AddToHeader adds sig to the Signature-Input and Signature fields in h.
+// The existing signatures are kept byte-for-byte identical.
+func AddToHeader(h sfv.Header, sig Signature) error {
+	if !sfv.IsValidKey(sig.Label) {
+		return fmt.Errorf("httpsig: invalid label %q", sig.Label)
+	}
+	if len(sig.Signature) == 0 {
+		return errors.New("httpsig: the signature is empty")
+	}
+	item, err := sig.Params.Item()
+	if err != nil {
+		return err
+	}
+
+	inputs, err := sfv.EditDictionary(h.Values(SignatureInputField))
+	if err != nil {
+		return err
+	}
+	sigs, err := sfv.EditDictionary(h.Values(SignatureField))
+	if err != nil {
+		return err
+	}
+	if _, ok := inputs.Get(sig.Label); ok {
+		return fmt.Errorf("httpsig: signature %q already exists", sig.Label)
+	}
+	if _, ok := sigs.Get(sig.Label); ok {
+		return fmt.Errorf("httpsig: signature %q already exists", sig.Label)
+	}
+	if err := inputs.Set(sig.Label, item); err != nil {
+		return err
+	}
+	if err := sigs.Set(sig.Label, sfv.Item{Value: sig.Signature}); err != nil {
+		return err
+	}
+	h.Set(SignatureInputField, inputs.String())
+	h.Set(SignatureField, sigs.String())
+	return nil
+}
@@ -0,0 +1,170 @@
+package httpsig
+
+import (
+	"bytes"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shogo82148/go-sfv"
+)
+
+// the example in RFC 9421 Section 4.1.
+const (
+	exampleInput     = `sig1=("@method" "@target-uri" "@authority" "content-digest" "cache-control");created=1618884475;keyid="test-key-rsa-pss"`
+	exampleSignature = `sig1=:P0wLUszWQjoi54udOtydf9IWTfNhy+r53jGFj9XZuP4uKwxyJo1RSHi+oEF1FuX6O29d+lbxwwBao1BAgadijW+7O/PyezlTnqAOVPWx9GlyntiCiHzC87qmSQjvu1CFyFuWSjdGa3qLYYlNm7pVaJFalQiKWnUaqfT4LyttaXyoyZW84jS8gyarxAiWI97mPXU+OVM64+HVBHmnEsS+lTeIsEQo36T3NFf2CujWARPQg53r58RmpZ+J9eKR2CD6IJQvacn5A4Ix5BUAVGqlyp8JYm+S/CWJi31PNUjRRCusCVRj05NrxABNFv3r5S9IXf2fYJK+eyW4AiGVMvMcOg==:`
+)
+
+func TestParseSignatureInput(t *testing.T) {
+	si, err := ParseSignatureInput([]string{exampleInput})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := SignatureInput{
+		{
+			Label: "sig1",
+			Params: SignatureParams{
+				Components: []Component{
+					{Name: "@method"},
+					{Name: "@target-uri"},
+					{Name: "@authority"},
+					{Name: "content-digest"},
+					{Name: "cache-control"},
+				},
+				Created: time.Unix(1618884475, 0),
+				KeyID:   "test-key-rsa-pss",
+			},
+		},
+	}
+	if !reflect.DeepEqual(si, want) {
+		t.Errorf("want %#v, got %#v", want, si)
+	}
+
+	got, err := si.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != exampleInput {
+		t.Errorf("want %q, got %q", exampleInput, got)
+	}
+}
+
+func TestParseSignatureInput_invalid(t *testing.T) {
+	for _, field := range []string{
+		`sig1=("@method",`,
+		`sig1="@method"`,
+		`sig1=(@method)`,
+		`sig1=("@method" "@method")`,
+		`sig1=("@signature-params")`,
+		`sig1=("Content-Type")`,
+		`sig1=("@method");created="now"`,
+		`sig1=("@method");keyid=foo`,
+	} {
+		if _, err := ParseSignatureInput([]string{field}); err == nil {
+			t.Errorf("%q: want error, but not", field)
+		}
+	}
+}
+
+func TestParseSignature(t *testing.T) {
+	sigs, err := ParseSignature([]string{exampleSignature})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, ok := sigs.Get("sig1")
+	if !ok || len(sig) != 256 || !bytes.HasPrefix(sig, []byte{0x3f, 0x4c, 0x0b}) {
+		t.Errorf("unexpected signature: %x", sig)
+	}
+
+	got, err := sigs.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != exampleSignature {
+		t.Errorf("want %q, got %q", exampleSignature, got)
+	}
+
+	if _, err := ParseSignature([]string{`sig1="not a byte sequence"`}); err == nil {
+		t.Error("want error, but not")
+	}
+}
+
+func TestCorrelate(t *testing.T) {
+	h := make(http.Header)
+	h.Set("Signature-Input", exampleInput+`, sig2=("@status");tag="x"`)
+	h.Set("Signature", `sig2=:AQID:, `+exampleSignature)
+
+	sigs, err := FromHeader(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sigs) != 2 {
+		t.Fatalf("want 2 signatures, got %d", len(sigs))
+	}
+	if sigs[0].Label != "sig1" || sigs[0].Params.KeyID != "test-key-rsa-pss" || len(sigs[0].Signature) != 256 {
+		t.Errorf("unexpected signature: %#v", sigs[0])
+	}
+	if sigs[1].Label != "sig2" || sigs[1].Params.Tag != "x" || !bytes.Equal(sigs[1].Signature, []byte{1, 2, 3}) {
+		t.Errorf("unexpected signature: %#v", sigs[1])
+	}
+
+	// missing labels
+	h.Set("Signature", exampleSignature)
+	if _, err := FromHeader(h); err == nil {
+		t.Error("want error, but not")
+	}
+	h.Set("Signature-Input", exampleInput)
+	h.Set("Signature", exampleSignature+`, sig3=:AQID:`)
+	if _, err := FromHeader(h); err == nil {
+		t.Error("want error, but not")
+	}
+}
+
+func TestAddToHeader(t *testing.T) {
+	h := make(http.Header)
+	h.Set("Signature-Input", exampleInput)
+	h.Set("Signature", exampleSignature)
+
+	sig := Signature{
+		Label: "proxy_sig",
+		Params: SignatureParams{
+			Components: []Component{
+				{Name: "@method"},
+				{Name: "signature", Parameters: sfv.Parameters{{Key: "key", Value: "sig1"}}},
+			},
+			Created: time.Unix(1618884480, 0),
+			Alg:     "rsa-pss-sha512",
+		},
+		Signature: []byte{1, 2, 3},
+	}
+	if err := AddToHeader(h, sig); err != nil {
+		t.Fatal(err)
+	}
+	wantInput := exampleInput + `, proxy_sig=("@method" "signature";key="sig1");created=1618884480;alg="rsa-pss-sha512"`
+	if got := h.Get("Signature-Input"); got != wantInput {
+		t.Errorf("want %q, got %q", wantInput, got)
+	}
+	wantSig := exampleSignature + `, proxy_sig=:AQID:`
+	if got := h.Get("Signature"); got != wantSig {
+		t.Errorf("want %q, got %q", wantSig, got)
+	}
+
+	sigs, err := FromHeader(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(sigs[1], sig) {
+		t.Errorf("want %#v, got %#v", sig, sigs[1])
+	}
+
+	// the label already exists.
+	if err := AddToHeader(h, sig); err == nil {
+		t.Error("want error, but not")
+	}
+	// invalid label
+	sig.Label = "Proxy"
+	if err := AddToHeader(h, sig); err == nil {
+		t.Error("want error, but not")
+	}
+}
@@ -0,0 +1,63 @@
+// Package fieldutil provides the helpers shared by the packages of the structured fields, e.g. cachestatus and proxystatus.
+package fieldutil
+
+import (
+	"strings"
+
+	"github.com/shogo82148/go-sfv"
+)
+
+// TokenOrString returns s as a Token if possible, otherwise as a String.
+func TokenOrString(s string) sfv.Value {
+	if t := sfv.Token(s); t.Valid() {
+		return t
+	}
+	return s
+}
+
+// StringOf returns the content of v if it is a Token or a String.
+func StringOf(v sfv.Value) (string, bool) {
+	switch v := v.(type) {
+	case sfv.Token:
+		return string(v), true
+	case string:
+		return v, true
+	}
+	return "", false
+}
+
+// ReservedParam returns the key of the first parameter in extra that is one of keys,
+// i.e. the parameter that must be set by the dedicated field instead of Extra.
+func ReservedParam(extra sfv.Parameters, keys ...string) (string, bool) {
+	for _, param := range extra {
+		for _, key := range keys {
+			if param.Key == key {
+				return key, true
+			}
+		}
+	}
+	return "", false
+}
+
+// AppendListMember appends item to the received List fields, and returns the new field value.
+// The received members are kept byte-for-byte identical.
+func AppendListMember(fields []string, item sfv.Item) (string, error) {
+	editor, err := sfv.EditList(fields)
+	if err != nil {
+		return "", err
+	}
+	if err := editor.Append(item); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(editor.String()), nil
+}
+
+// AppendHeader appends item to the List field with the name in h.
+func AppendHeader(h sfv.Header, name string, item sfv.Item) error {
+	v, err := AppendListMember(h.Values(name), item)
+	if err != nil {
+		return err
+	}
+	h.Set(name, v)
+	return nil
+}
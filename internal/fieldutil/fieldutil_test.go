@@ -0,0 +1,50 @@
+package fieldutil
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/shogo82148/go-sfv"
+)
+
+func TestTokenOrString(t *testing.T) {
+	if got := TokenOrString("main"); got != sfv.Token("main") {
+		t.Errorf("want token, got %#v", got)
+	}
+	if got := TokenOrString("the endpoint"); got != "the endpoint" {
+		t.Errorf("want string, got %#v", got)
+	}
+}
+
+func TestStringOf(t *testing.T) {
+	if s, ok := StringOf(sfv.Token("foo")); !ok || s != "foo" {
+		t.Errorf("unexpected result: %q, %t", s, ok)
+	}
+	if s, ok := StringOf("foo bar"); !ok || s != "foo bar" {
+		t.Errorf("unexpected result: %q, %t", s, ok)
+	}
+	if _, ok := StringOf(int64(1)); ok {
+		t.Error("want not ok, but ok")
+	}
+}
+
+func TestReservedParam(t *testing.T) {
+	extra := sfv.Parameters{{Key: "x", Value: true}, {Key: "ttl", Value: int64(1)}}
+	if key, ok := ReservedParam(extra, "hit", "ttl"); !ok || key != "ttl" {
+		t.Errorf("unexpected result: %q, %t", key, ok)
+	}
+	if _, ok := ReservedParam(extra, "hit"); ok {
+		t.Error("want not ok, but ok")
+	}
+}
+
+func TestAppendHeader(t *testing.T) {
+	h := http.Header{}
+	h.Add("Cache-Status", "  OriginCache; hit")
+	if err := AppendHeader(h, "Cache-Status", sfv.Item{Value: sfv.Token("CDN")}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := h.Get("Cache-Status"), "OriginCache; hit, CDN"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
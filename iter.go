@@ -0,0 +1,88 @@
+//go:build go1.23
+
+package sfv
+
+import "iter"
+
+// All returns an iterator over the key-value pairs of the parameters.
+func (param Parameters) All() iter.Seq2[string, Value] {
+	return func(yield func(string, Value) bool) {
+		for _, kv := range param {
+			if !yield(kv.Key, kv.Value) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the indexes and the items of the list.
+func (list List) All() iter.Seq2[int, Item] {
+	return func(yield func(int, Item) bool) {
+		for i, item := range list {
+			if !yield(i, item) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the indexes and the items of the inner list.
+func (list InnerList) All() iter.Seq2[int, Item] {
+	return func(yield func(int, Item) bool) {
+		for i, item := range list {
+			if !yield(i, item) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the key-item pairs of the dictionary.
+func (dict Dictionary) All() iter.Seq2[string, Item] {
+	return func(yield func(string, Item) bool) {
+		for _, kv := range dict {
+			if !yield(kv.Key, kv.Item) {
+				return
+			}
+		}
+	}
+}
+
+// AllValues returns an iterator over the bare items of v in order.
+// It descends into inner lists, and yields the values of their items instead of the inner lists.
+// The parameters are not yielded.
+func AllValues[T Item | List | Dictionary](v T) iter.Seq[Value] {
+	return func(yield func(Value) bool) {
+		switch v := any(v).(type) {
+		case Item:
+			yieldValues(v, yield)
+		case List:
+			for _, item := range v {
+				if !yieldValues(item, yield) {
+					return
+				}
+			}
+		case Dictionary:
+			for _, kv := range v {
+				if !yieldValues(kv.Item, yield) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// yieldValues yields the value of the item, or the values of the inner list.
+// It reports whether the iteration should continue.
+func yieldValues(item Item, yield func(Value) bool) bool {
+	list, ok := item.Value.(InnerList)
+	if !ok {
+		return yield(item.Value)
+	}
+	for _, item := range list {
+		if !yield(item.Value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+//go:build go1.23
+
+package sfv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDictionary_All(t *testing.T) {
+	dict, err := DecodeDictionary([]string{`a=1, b=2, c=3`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	var values []Value
+	for k, item := range dict.All() {
+		if k == "c" {
+			break
+		}
+		keys = append(keys, k)
+		values = append(values, item.Value)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("want %v, got %v", want, keys)
+	}
+	if want := []Value{int64(1), int64(2)}; !reflect.DeepEqual(values, want) {
+		t.Errorf("want %v, got %v", want, values)
+	}
+}
+
+func TestParameters_All(t *testing.T) {
+	item, err := DecodeItem([]string{`1;a=x;b;c="y"`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	var values []Value
+	for k, v := range item.Parameters.All() {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("want %v, got %v", want, keys)
+	}
+	if want := []Value{Token("x"), true, "y"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("want %v, got %v", want, values)
+	}
+}
+
+func TestList_All(t *testing.T) {
+	list, err := DecodeList([]string{`a, (b c), d`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var indexes []int
+	for i, item := range list.All() {
+		indexes = append(indexes, i)
+		if inner, ok := item.Value.(InnerList); ok {
+			for j, item := range inner.All() {
+				if item.Value != Token("b") {
+					t.Errorf("want b, got %v", item.Value)
+				}
+				if j != 0 {
+					t.Errorf("want 0, got %d", j)
+				}
+				break
+			}
+		}
+	}
+	if want := []int{0, 1, 2}; !reflect.DeepEqual(indexes, want) {
+		t.Errorf("want %v, got %v", want, indexes)
+	}
+}
+
+func TestAllValues(t *testing.T) {
+	list, err := DecodeList([]string{`a;x=1, (b c);y=2, d`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var values []Value
+	for v := range AllValues(list) {
+		values = append(values, v)
+	}
+	if want := []Value{Token("a"), Token("b"), Token("c"), Token("d")}; !reflect.DeepEqual(values, want) {
+		t.Errorf("want %v, got %v", want, values)
+	}
+
+	// stop early in an inner list.
+	values = values[:0]
+	for v := range AllValues(list) {
+		values = append(values, v)
+		if v == Token("b") {
+			break
+		}
+	}
+	if want := []Value{Token("a"), Token("b")}; !reflect.DeepEqual(values, want) {
+		t.Errorf("want %v, got %v", want, values)
+	}
+
+	dict, err := DecodeDictionary([]string{`a=(1 2), b`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values = values[:0]
+	for v := range AllValues(dict) {
+		values = append(values, v)
+	}
+	if want := []Value{int64(1), int64(2), true}; !reflect.DeepEqual(values, want) {
+		t.Errorf("want %v, got %v", want, values)
+	}
+
+	values = values[:0]
+	for v := range AllValues(Item{Value: "foo"}) {
+		values = append(values, v)
+	}
+	if want := []Value{"foo"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("want %v, got %v", want, values)
+	}
+}
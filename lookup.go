@@ -0,0 +1,102 @@
+package sfv
+
+import "errors"
+
+// LookupDictionaryMember decodes fields as a Dictionary,
+// and returns the last item associated with the given key.
+// The boolean result reports whether the key is found.
+//
+// Unlike DecodeDictionary, it decodes only the requested member,
+// and skips the other members without allocations and validation.
+// Use DecodeOptions.ValidateSkipped to validate the whole field.
+func LookupDictionaryMember(fields []string, key string) (Item, bool, error) {
+	state := &decodeState{
+		fields: fields,
+	}
+	return state.lookupDictionaryMember(key)
+}
+
+// LookupDictionaryMember is similar to the package-level LookupDictionaryMember, but uses the options.
+// RawMembers is not supported.
+func (opts DecodeOptions) LookupDictionaryMember(fields []string, key string) (Item, bool, error) {
+	state := &decodeState{
+		fields: fields,
+		opts:   &opts,
+	}
+	return state.lookupDictionaryMember(key)
+}
+
+func (s *decodeState) lookupDictionaryMember(key string) (Item, bool, error) {
+	validate := s.opts != nil && s.opts.ValidateSkipped
+
+	s.skipSPs()
+	if s.peek() == endOfInput {
+		// it is an empty dictionary
+		return Item{}, false, nil
+	}
+
+	var ret Item
+	var found bool
+	var prev Pos
+	for {
+		// decode keys
+		start := s.pos()
+		if err := s.skipKey(); err != nil {
+			return Item{}, false, err
+		}
+
+		switch {
+		case s.text(s.span(start)) == key:
+			// it is the requested member
+			if found && s.opts != nil {
+				if err := s.duplicateKey(key, start, prev, false); err != nil {
+					return Item{}, false, err
+				}
+			}
+			found = true
+			prev = start
+
+			var err error
+			if s.peek() == '=' {
+				s.next() // skip '='
+				ret, err = s.decodeItemOrInnerItem()
+			} else {
+				ret.Value = true
+				ret.Parameters, err = s.decodeParameters()
+			}
+			if err != nil {
+				return Item{}, false, err
+			}
+
+		case validate:
+			if s.peek() == '=' {
+				s.next() // skip '='
+				if err := s.skipItemOrInnerList(); err != nil {
+					return Item{}, false, err
+				}
+			} else if err := s.skipParameters(); err != nil {
+				return Item{}, false, err
+			}
+
+		default:
+			s.skipMemberLoosely()
+		}
+
+		// skip commas
+		s.skipOWS()
+		ch := s.peek()
+		if ch == endOfInput {
+			break
+		}
+		if ch != ',' {
+			return Item{}, false, s.errUnexpectedCharacter()
+		}
+		s.next() // skip ','
+		s.skipOWS()
+		if s.peek() == endOfInput {
+			// it is trailing comma.
+			return Item{}, false, errors.New("sfv: trailing comma is not allowed")
+		}
+	}
+	return ret, found, nil
+}
@@ -0,0 +1,116 @@
+package sfv
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestLookupDictionaryMember(t *testing.T) {
+	strict := DecodeOptions{
+		ValidateSkipped: true,
+	}
+	for _, tt := range allTestCases(t) {
+		if tt.HeaderType != headerTypeDictionary {
+			continue
+		}
+		tt := tt
+		t.Run(tt.Name, func(t *testing.T) {
+			dict, wantErr := DecodeDictionary(tt.Raw)
+
+			keys := []string{"missing"}
+			for _, member := range dict {
+				keys = append(keys, member.Key)
+			}
+			for _, key := range keys {
+				// strict mode reports the same errors as DecodeDictionary.
+				item, found, err := strict.LookupDictionaryMember(tt.Raw, key)
+				if (err != nil) != (wantErr != nil) {
+					t.Fatalf("key %q: want error %v, got %v", key, wantErr, err)
+				}
+				if err != nil {
+					continue
+				}
+				want := dict.Get(key)
+				if found != (want.Value != nil) {
+					t.Errorf("key %q: want found %v, got %v", key, want.Value != nil, found)
+				}
+				if !reflect.DeepEqual(item, want) {
+					t.Errorf("key %q: want %#v, got %#v", key, want, item)
+				}
+
+				// loose mode finds the same item.
+				item, found, err = LookupDictionaryMember(tt.Raw, key)
+				if err != nil {
+					t.Fatalf("key %q: unexpected error: %v", key, err)
+				}
+				if found != (want.Value != nil) {
+					t.Errorf("key %q: want found %v, got %v", key, want.Value != nil, found)
+				}
+				if !reflect.DeepEqual(item, want) {
+					t.Errorf("key %q: want %#v, got %#v", key, want, item)
+				}
+			}
+		})
+	}
+}
+
+func TestLookupDictionaryMember_loose(t *testing.T) {
+	fields := []string{`a=1, b=("x, y" (z)), c="a, \"b\""`, `d=?1;e=%"%ff", u=3;x`}
+
+	// the members other than the requested one are not validated.
+	item, found, err := LookupDictionaryMember(fields, "u")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Item{
+		Value:      int64(3),
+		Parameters: Parameters{{Key: "x", Value: true}},
+	}
+	if !found || !reflect.DeepEqual(item, want) {
+		t.Errorf("want %v, got %v", want, item)
+	}
+
+	_, _, err = DecodeOptions{ValidateSkipped: true}.LookupDictionaryMember(fields, "u")
+	if err == nil {
+		t.Error("want error, but not")
+	}
+
+	// the requested member is validated.
+	if _, _, err := LookupDictionaryMember(fields, "d"); err == nil {
+		t.Error("want error, but not")
+	}
+}
+
+func TestLookupDictionaryMember_duplicated(t *testing.T) {
+	opts := DecodeOptions{
+		DisallowDuplicateKeys: true,
+	}
+	if _, _, err := opts.LookupDictionaryMember([]string{"a=1, b=2", "a=3"}, "a"); err == nil {
+		t.Error("want error, but not")
+	}
+	item, found, err := opts.LookupDictionaryMember([]string{"a=1, b=2", "a=3"}, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || item.Value != int64(2) {
+		t.Errorf("want 2, got %v", item.Value)
+	}
+}
+
+func BenchmarkLookupDictionaryMember(b *testing.B) {
+	var fields []string
+	for i := 0; i < 1024; i++ {
+		fields = append(fields, fmt.Sprintf(`key%d=("foo" "bar");a=1;b=2.5, `, i)+`u=3`)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		got, _, err := LookupDictionaryMember(fields, "u")
+		if err != nil {
+			b.Error(err)
+		}
+		runtime.KeepAlive(got)
+	}
+}
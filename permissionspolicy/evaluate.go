@@ -0,0 +1,80 @@
+package permissionspolicy
+
+// Default is the default allowlist of a policy-controlled feature,
+// that is used if the feature is not declared in the policy.
+type Default int
+
+const (
+	// DefaultSelf means the feature is allowed in the same-origin documents by default.
+	DefaultSelf Default = iota
+
+	// DefaultAll means the feature is allowed in all documents by default.
+	DefaultAll
+)
+
+// defaultAll is the well-known features whose default allowlist is *.
+// The other features are treated as DefaultSelf.
+var defaultAll = map[string]bool{
+	"ch-ua":           true,
+	"ch-ua-mobile":    true,
+	"ch-ua-platform":  true,
+	"document-domain": true,
+	"sync-xhr":        true,
+}
+
+// DefaultOf returns the default allowlist of the feature.
+func DefaultOf(feature string) Default {
+	if defaultAll[feature] {
+		return DefaultAll
+	}
+	return DefaultSelf
+}
+
+// Frame is a frame embedded in the document, e.g. an iframe element.
+type Frame struct {
+	// Origin is the origin of the document in the frame.
+	Origin string
+
+	// Allow is the container policy, i.e. the allow attribute of the iframe element.
+	// The keyword 'src' in the attribute is given as Origin in the allowlist.
+	// It is nil if the element has no allow attribute.
+	Allow Policy
+}
+
+// Allowed reports whether the feature is allowed for origin,
+// in the document from self whose Permissions-Policy is p.
+// If origin is not self, it reports whether the feature is allowed in a frame from origin embedded in the document,
+// assuming that the iframe element has no allow attribute.
+// The default allowlist of the undeclared feature is DefaultOf(feature).
+func (p Policy) Allowed(feature, origin, self string) bool {
+	return p.AllowedInFrame(feature, Frame{Origin: origin}, self)
+}
+
+// AllowedInFrame reports whether the feature is allowed in the frame f
+// embedded in the document from self whose Permissions-Policy is p.
+// It follows "Define an inherited policy for feature in container at origin" in the specification.
+// As Allowed and Allowlist.Matches, the frame comes before self.
+// The default allowlist of the feature is DefaultOf(feature).
+func (p Policy) AllowedInFrame(feature string, f Frame, self string) bool {
+	return p.AllowedWithDefault(feature, f, self, DefaultOf(feature))
+}
+
+// AllowedWithDefault is similar to AllowedInFrame, but uses def as the default allowlist.
+func (p Policy) AllowedWithDefault(feature string, f Frame, self string, def Default) bool {
+	// the feature must be enabled in the document itself and for the origin of the frame,
+	// before it is delegated to the frame.
+	if d, ok := p.Lookup(feature); ok {
+		if !d.Allowlist.Matches(self, self) || !d.Allowlist.Matches(f.Origin, self) {
+			return false
+		}
+	}
+
+	// the container policy overrides the default allowlist.
+	if d, ok := f.Allow.Lookup(feature); ok {
+		return d.Allowlist.Matches(f.Origin, self)
+	}
+	if def == DefaultAll {
+		return true
+	}
+	return Allowlist{Self: true}.Matches(f.Origin, self)
+}
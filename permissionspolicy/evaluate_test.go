@@ -0,0 +1,85 @@
+package permissionspolicy
+
+import (
+	"testing"
+)
+
+func TestPolicy_Allowed(t *testing.T) {
+	const self = "https://example.com"
+	p, err := Parse([]string{`geolocation=(self "https://maps.example.net"), camera=(), fullscreen=*, payment=("https://pay.example.org"), sync-xhr=()`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		feature string
+		origin  string
+		want    bool
+	}{
+		{"geolocation", self, true},
+		{"geolocation", "https://EXAMPLE.com:443", true},
+		{"geolocation", "https://maps.example.net", false}, // the default allowlist is 'self'.
+		{"geolocation", "http://maps.example.net", false},
+		{"geolocation", "https://evil.example", false},
+		{"camera", self, false},
+		{"camera", "https://maps.example.net", false},
+		{"fullscreen", "https://evil.example", false},
+
+		// payment is not allowed in the document itself, so it can't be delegated.
+		{"payment", self, false},
+		{"payment", "https://pay.example.org", false},
+
+		// the features that are not declared use the default allowlists.
+		{"microphone", self, true},
+		{"microphone", "https://maps.example.net", false},
+		{"ch-ua", "https://maps.example.net", true},
+		{"sync-xhr", self, false},
+
+		{"geolocation", "not an origin", false},
+	}
+	for _, tt := range tests {
+		if got := p.Allowed(tt.feature, tt.origin, self); got != tt.want {
+			t.Errorf("%s for %s: want %t, got %t", tt.feature, tt.origin, tt.want, got)
+		}
+	}
+
+	if !p.AllowedWithDefault("microphone", Frame{Origin: "https://evil.example"}, self, DefaultAll) {
+		t.Error("want microphone to be allowed with DefaultAll")
+	}
+}
+
+func TestPolicy_AllowedInFrame(t *testing.T) {
+	const self = "https://example.com"
+	p, err := Parse([]string{`geolocation=(self "https://maps.example.net"), camera=(), fullscreen=*, payment=("https://pay.example.org")`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		feature string
+		origin  string
+		allow   Policy
+		want    bool
+	}{
+		{"geolocation", "https://maps.example.net", Policy{{Feature: "geolocation", Allowlist: Allowlist{Origins: []string{"https://maps.example.net"}}}}, true},
+		{"geolocation", "https://maps.example.net", Policy{{Feature: "geolocation", Allowlist: Allowlist{Self: true}}}, false},
+		{"geolocation", "https://maps.example.net", Policy{{Feature: "camera", Allowlist: Allowlist{All: true}}}, false},
+		{"fullscreen", "https://evil.example", Policy{{Feature: "fullscreen", Allowlist: Allowlist{All: true}}}, true},
+
+		// the container policy can't allow the features that the document doesn't allow for the origin.
+		{"geolocation", "https://evil.example", Policy{{Feature: "geolocation", Allowlist: Allowlist{All: true}}}, false},
+		{"camera", self, Policy{{Feature: "camera", Allowlist: Allowlist{All: true}}}, false},
+		{"payment", "https://pay.example.org", Policy{{Feature: "payment", Allowlist: Allowlist{All: true}}}, false},
+
+		// the features that are not declared in the document.
+		{"microphone", "https://maps.example.net", Policy{{Feature: "microphone", Allowlist: Allowlist{Origins: []string{"https://maps.example.net"}}}}, true},
+		{"ch-ua", "https://maps.example.net", Policy{{Feature: "ch-ua", Allowlist: Allowlist{}}}, false},
+		{"ch-ua", "https://maps.example.net", nil, true},
+	}
+	for _, tt := range tests {
+		got := p.AllowedInFrame(tt.feature, Frame{Origin: tt.origin, Allow: tt.allow}, self)
+		if got != tt.want {
+			t.Errorf("%s for %s with %#v: want %t, got %t", tt.feature, tt.origin, tt.allow, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// Package permissionspolicy implements the Permissions-Policy field defined in W3C Permissions Policy.
+package permissionspolicy
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/shogo82148/go-sfv"
+	"github.com/shogo82148/go-sfv/internal/fieldutil"
+)
+
+// FieldName is the name of the Permissions-Policy field.
+const FieldName = "Permissions-Policy"
+
+// Allowlist is the set of origins that a feature is allowed for.
+type Allowlist struct {
+	// All is the token *, that means the feature is allowed for all origins.
+	All bool
+
+	// Self is the token self, that means the feature is allowed for the origin of the document.
+	Self bool
+
+	// Origins is the serialized origins, e.g. "https://example.com".
+	Origins []string
+}
+
+// Matches reports whether origin matches the allowlist.
+// self is the origin of the document that has the policy.
+func (a Allowlist) Matches(origin, self string) bool {
+	if a.All {
+		return true
+	}
+	origin, ok := normalizeOrigin(origin)
+	if !ok {
+		return false
+	}
+	if a.Self {
+		if s, ok := normalizeOrigin(self); ok && s == origin {
+			return true
+		}
+	}
+	for _, o := range a.Origins {
+		if o, ok := normalizeOrigin(o); ok && o == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// Directive is a member of the Permissions-Policy field.
+type Directive struct {
+	// Feature is the name of the policy-controlled feature, e.g. "geolocation".
+	Feature string
+
+	// Allowlist is the origins that the feature is allowed for.
+	Allowlist Allowlist
+
+	// ReportTo is the report-to parameter, or empty if it is omitted.
+	ReportTo string
+
+	// Extra is the parameters other than report-to.
+	Extra sfv.Parameters
+}
+
+// Policy is the Permissions-Policy field.
+type Policy []Directive
+
+// Parse parses the Permissions-Policy fields.
+func Parse(fields []string) (Policy, error) {
+	dict, err := sfv.DecodeDictionary(fields)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDictionary(dict)
+}
+
+// ParseDictionary converts dict into the policy.
+// As the specification requires, the unknown tokens and the invalid origins in the allowlists are ignored.
+func ParseDictionary(dict sfv.Dictionary) (Policy, error) {
+	if len(dict) == 0 {
+		return nil, nil
+	}
+	p := make(Policy, 0, len(dict))
+	for _, member := range dict {
+		d := Directive{
+			Feature: member.Key,
+		}
+		if list, ok := member.Item.Value.(sfv.InnerList); ok {
+			for _, item := range list {
+				d.Allowlist.add(item.Value)
+			}
+		} else {
+			d.Allowlist.add(member.Item.Value)
+		}
+
+		for _, param := range member.Item.Parameters {
+			if param.Key != "report-to" {
+				d.Extra = append(d.Extra, param)
+				continue
+			}
+			var ok bool
+			if d.ReportTo, ok = fieldutil.StringOf(param.Value); !ok {
+				return nil, fmt.Errorf("permissionspolicy: invalid parameter report-to=%v of %q", param.Value, d.Feature)
+			}
+		}
+		p = append(p, d)
+	}
+	return p, nil
+}
+
+func (a *Allowlist) add(v sfv.Value) {
+	switch v := v.(type) {
+	case sfv.Token:
+		switch v {
+		case "*":
+			a.All = true
+		case "self":
+			a.Self = true
+		}
+	case string:
+		if origin, ok := normalizeOrigin(v); ok {
+			a.Origins = append(a.Origins, origin)
+		}
+	}
+}
+
+// normalizeOrigin parses s as a URL, and returns its serialized origin.
+func normalizeOrigin(s string) (string, bool) {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Host == "" || u.Opaque != "" {
+		return "", false
+	}
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Host)
+	switch scheme {
+	case "http", "ws":
+		host = strings.TrimSuffix(host, ":80")
+	case "https", "wss":
+		host = strings.TrimSuffix(host, ":443")
+	}
+	return scheme + "://" + host, true
+}
+
+// Validate checks that d can be serialized as a member of the Permissions-Policy field.
+func (d Directive) Validate() error {
+	if !sfv.IsValidKey(d.Feature) {
+		return fmt.Errorf("permissionspolicy: invalid feature %q", d.Feature)
+	}
+	for _, o := range d.Allowlist.Origins {
+		if _, ok := normalizeOrigin(o); !ok {
+			return fmt.Errorf("permissionspolicy: %q has invalid origin %q", d.Feature, o)
+		}
+	}
+	if key, ok := fieldutil.ReservedParam(d.Extra, "report-to"); ok {
+		return fmt.Errorf("permissionspolicy: %q has the parameter %q in Extra", d.Feature, key)
+	}
+	return nil
+}
+
+// Item converts d into the member value of the Permissions-Policy field.
+// The allowlist is serialized as the token * if All is true, otherwise as an inner list.
+func (d Directive) Item() (sfv.Item, error) {
+	if err := d.Validate(); err != nil {
+		return sfv.Item{}, err
+	}
+
+	var item sfv.Item
+	if d.Allowlist.All {
+		item.Value = sfv.Token("*")
+	} else {
+		list := sfv.InnerList{}
+		if d.Allowlist.Self {
+			list = append(list, sfv.Item{Value: sfv.Token("self")})
+		}
+		for _, o := range d.Allowlist.Origins {
+			origin, _ := normalizeOrigin(o)
+			list = append(list, sfv.Item{Value: origin})
+		}
+		item.Value = list
+	}
+	if d.ReportTo != "" {
+		item.Parameters = append(item.Parameters, sfv.Parameter{Key: "report-to", Value: fieldutil.TokenOrString(d.ReportTo)})
+	}
+	item.Parameters = append(item.Parameters, d.Extra...)
+
+	if err := sfv.ValidateDictionary(sfv.Dictionary{{Key: d.Feature, Item: item}}); err != nil {
+		return sfv.Item{}, err
+	}
+	return item, nil
+}
+
+// Dictionary converts p into the Permissions-Policy field.
+func (p Policy) Dictionary() (sfv.Dictionary, error) {
+	dict := make(sfv.Dictionary, 0, len(p))
+	for _, d := range p {
+		item, err := d.Item()
+		if err != nil {
+			return nil, err
+		}
+		dict = append(dict, sfv.DictMember{Key: d.Feature, Item: item})
+	}
+	return dict, nil
+}
+
+// Encode serializes p.
+func (p Policy) Encode() (string, error) {
+	dict, err := p.Dictionary()
+	if err != nil {
+		return "", err
+	}
+	return sfv.EncodeDictionary(dict)
+}
+
+// Lookup returns the last directive of the feature, that is the one in effect.
+func (p Policy) Lookup(feature string) (Directive, bool) {
+	for i := len(p) - 1; i >= 0; i-- {
+		if p[i].Feature == feature {
+			return p[i], true
+		}
+	}
+	return Directive{}, false
+}
+
+// SetHeader sets the Permissions-Policy field in h.
+// The field is removed if p is empty.
+func SetHeader(h sfv.Header, p Policy) error {
+	if len(p) == 0 {
+		h.Del(FieldName)
+		return nil
+	}
+	v, err := p.Encode()
+	if err != nil {
+		return err
+	}
+	h.Set(FieldName, v)
+	return nil
+}
@@ -0,0 +1,122 @@
+package permissionspolicy
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/shogo82148/go-sfv"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		field string
+		want  Policy
+	}{
+		{
+			`geolocation=(self "https://example.com"), camera=(), fullscreen=*`,
+			Policy{
+				{Feature: "geolocation", Allowlist: Allowlist{Self: true, Origins: []string{"https://example.com"}}},
+				{Feature: "camera"},
+				{Feature: "fullscreen", Allowlist: Allowlist{All: true}},
+			},
+		},
+		{
+			`geolocation=self, payment="https://PAY.example.com:443/checkout"`,
+			Policy{
+				{Feature: "geolocation", Allowlist: Allowlist{Self: true}},
+				{Feature: "payment", Allowlist: Allowlist{Origins: []string{"https://pay.example.com"}}},
+			},
+		},
+		{
+			// the unknown tokens, invalid origins and the other types are ignored.
+			`microphone=(none "not an origin" 1 "http://example.com:8080")`,
+			Policy{
+				{Feature: "microphone", Allowlist: Allowlist{Origins: []string{"http://example.com:8080"}}},
+			},
+		},
+		{
+			`camera=();report-to=endpoint, usb=(self);report-to="main endpoint";x=1`,
+			Policy{
+				{Feature: "camera", ReportTo: "endpoint"},
+				{Feature: "usb", Allowlist: Allowlist{Self: true}, ReportTo: "main endpoint", Extra: sfv.Parameters{{Key: "x", Value: int64(1)}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		got, err := Parse([]string{tt.field})
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.field, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: want %#v, got %#v", tt.field, tt.want, got)
+		}
+	}
+
+	for _, field := range []string{
+		`geolocation=(self`,
+		`Geolocation=self`,
+		`camera=();report-to=1`,
+	} {
+		if _, err := Parse([]string{field}); err == nil {
+			t.Errorf("%q: want error, but not", field)
+		}
+	}
+}
+
+func TestPolicy_Encode(t *testing.T) {
+	p := Policy{
+		{Feature: "geolocation", Allowlist: Allowlist{Self: true, Origins: []string{"https://Example.com:443"}}},
+		{Feature: "camera", ReportTo: "endpoint"},
+		{Feature: "fullscreen", Allowlist: Allowlist{All: true, Self: true}},
+		{Feature: "usb", ReportTo: "main endpoint"},
+	}
+	got, err := p.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `geolocation=(self "https://example.com"), camera=();report-to=endpoint, fullscreen=*, usb=();report-to="main endpoint"`
+	if got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	h := make(http.Header)
+	if err := SetHeader(h, p); err != nil {
+		t.Fatal(err)
+	}
+	if got := h.Get(FieldName); got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	if err := SetHeader(h, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := h[FieldName]; ok {
+		t.Error("want the field to be removed")
+	}
+
+	invalid := []Policy{
+		{{Feature: "Camera"}},
+		{{Feature: "camera", Allowlist: Allowlist{Origins: []string{"example.com"}}}},
+		{{Feature: "camera", Extra: sfv.Parameters{{Key: "report-to", Value: "a"}}}},
+	}
+	for _, p := range invalid {
+		if _, err := p.Encode(); err == nil {
+			t.Errorf("%#v: want error, but not", p)
+		}
+	}
+}
+
+func TestPolicy_Lookup(t *testing.T) {
+	p, err := Parse([]string{"camera=(), camera=self", "geolocation=*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, ok := p.Lookup("camera")
+	if !ok || !d.Allowlist.Self {
+		t.Errorf("unexpected directive: %#v", d)
+	}
+	if _, ok := p.Lookup("usb"); ok {
+		t.Error("want not found, but found")
+	}
+}
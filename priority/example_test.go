@@ -0,0 +1,31 @@
+package priority_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/shogo82148/go-sfv/priority"
+)
+
+func ExampleParse() {
+	h := make(http.Header)
+	h.Set("Priority", "u=5, i")
+
+	p, err := priority.Parse(h.Values("Priority"))
+	if err != nil {
+		// RFC 9218 requires to ignore the invalid field.
+		p = priority.Default
+	}
+	fmt.Println(p.Urgency, p.Incremental)
+
+	// apply a PRIORITY_UPDATE frame.
+	p, err = p.Merge("u=0")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(p)
+
+	//Output:
+	// 5 true
+	// u=0, i
+}
@@ -0,0 +1,115 @@
+// Package priority implements the Priority field defined in RFC 9218 Extensible Prioritization Scheme for HTTP.
+package priority
+
+import (
+	"github.com/shogo82148/go-sfv"
+)
+
+const (
+	// MinUrgency is the highest priority.
+	MinUrgency = 0
+
+	// MaxUrgency is the lowest priority.
+	MaxUrgency = 7
+
+	// DefaultUrgency is the urgency used when the u parameter is omitted.
+	DefaultUrgency = 3
+)
+
+// Priority is the priority parameters defined in RFC 9218 Section 4.
+type Priority struct {
+	// Urgency is the u parameter, between MinUrgency and MaxUrgency.
+	// Lower values mean higher priority.
+	Urgency int
+
+	// Incremental is the i parameter.
+	// It indicates that the response can be processed incrementally.
+	Incremental bool
+}
+
+// Default is the priority used when the parameters are omitted.
+var Default = Priority{
+	Urgency:     DefaultUrgency,
+	Incremental: false,
+}
+
+// Parse parses the Priority fields.
+// If the fields are omitted, it returns Default.
+// If the fields can't be parsed, it returns Default and the error;
+// RFC 9218 requires to ignore such fields, so callers can use the result regardless of the error.
+func Parse(fields []string) (Priority, error) {
+	dict, err := sfv.DecodeDictionary(fields)
+	if err != nil {
+		return Default, err
+	}
+	return ParseDictionary(dict), nil
+}
+
+// ParseDictionary returns the priority in dict.
+// The omitted parameters have the default values.
+// Unknown members, out-of-range values and values of unexpected types are ignored.
+func ParseDictionary(dict sfv.Dictionary) Priority {
+	return Default.MergeDictionary(dict)
+}
+
+// MergeDictionary returns p updated with the parameters in dict.
+// The parameters omitted in dict keep the values in p.
+// Unknown members, out-of-range values and values of unexpected types are ignored.
+func (p Priority) MergeDictionary(dict sfv.Dictionary) Priority {
+	for _, member := range dict {
+		switch member.Key {
+		case "u":
+			if u, ok := member.Item.Value.(int64); ok && u >= MinUrgency && u <= MaxUrgency {
+				p.Urgency = int(u)
+			}
+		case "i":
+			if i, ok := member.Item.Value.(bool); ok {
+				p.Incremental = i
+			}
+		}
+	}
+	return p
+}
+
+// Merge returns p updated with the Priority Field Value of a PRIORITY_UPDATE frame.
+// The parameters omitted in the update keep the values in p, that are typically from the Priority header field.
+// If the update can't be parsed, it returns p and the error.
+func (p Priority) Merge(update string) (Priority, error) {
+	dict, err := sfv.DecodeDictionary([]string{update})
+	if err != nil {
+		return p, err
+	}
+	return p.MergeDictionary(dict), nil
+}
+
+// Dictionary returns the minimal dictionary that represents p.
+// The parameters with the default values are omitted.
+// The urgency out of the range is clamped between MinUrgency and MaxUrgency.
+func (p Priority) Dictionary() sfv.Dictionary {
+	var dict sfv.Dictionary
+	if u := clampUrgency(p.Urgency); u != DefaultUrgency {
+		dict = append(dict, sfv.DictMember{
+			Key:  "u",
+			Item: sfv.Item{Value: int64(u)},
+		})
+	}
+	if p.Incremental {
+		dict = append(dict, sfv.DictMember{
+			Key:  "i",
+			Item: sfv.Item{Value: true},
+		})
+	}
+	return dict
+}
+
+// String returns the minimal serialization of p.
+// It returns an empty string if p is Default.
+// The urgency out of the range is clamped as Dictionary does.
+func (p Priority) String() string {
+	s, err := sfv.EncodeDictionary(p.Dictionary())
+	if err != nil {
+		// it never happens, because Dictionary returns only valid members.
+		return ""
+	}
+	return s
+}
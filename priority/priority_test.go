@@ -0,0 +1,130 @@
+package priority
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		fields []string
+		want   Priority
+	}{
+		{nil, Default},
+		{[]string{""}, Default},
+		{[]string{"u=5"}, Priority{Urgency: 5}},
+		{[]string{"i"}, Priority{Urgency: 3, Incremental: true}},
+		{[]string{"u=0, i"}, Priority{Urgency: 0, Incremental: true}},
+		{[]string{"u=1", "i=?1"}, Priority{Urgency: 1, Incremental: true}},
+
+		// the last member wins
+		{[]string{"u=1, u=2"}, Priority{Urgency: 2}},
+
+		// unknown members are ignored
+		{[]string{"u=1, foo=bar, i;x"}, Priority{Urgency: 1, Incremental: true}},
+
+		// out-of-range values are ignored
+		{[]string{"u=8"}, Default},
+		{[]string{"u=-1"}, Default},
+
+		// values of unexpected types are ignored
+		{[]string{`u="1", i=1`}, Default},
+		{[]string{`u=1.0`}, Default},
+		{[]string{`u=(1)`}, Default},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.fields)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.fields, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q: want %#v, got %#v", tt.fields, tt.want, got)
+		}
+	}
+}
+
+func TestParse_invalid(t *testing.T) {
+	got, err := Parse([]string{"u=1, "})
+	if err == nil {
+		t.Error("want error, but not")
+	}
+	if got != Default {
+		t.Errorf("want %#v, got %#v", Default, got)
+	}
+}
+
+func TestPriority_Merge(t *testing.T) {
+	header, err := Parse([]string{"u=1, i"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		update string
+		want   Priority
+	}{
+		{"", Priority{Urgency: 1, Incremental: true}},
+		{"u=5", Priority{Urgency: 5, Incremental: true}},
+		{"i=?0", Priority{Urgency: 1, Incremental: false}},
+		{"u=9, i=?0", Priority{Urgency: 1, Incremental: false}},
+	}
+	for _, tt := range tests {
+		got, err := header.Merge(tt.update)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.update, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q: want %#v, got %#v", tt.update, tt.want, got)
+		}
+	}
+
+	got, err := header.Merge("u=")
+	if err == nil {
+		t.Error("want error, but not")
+	}
+	if got != header {
+		t.Errorf("want %#v, got %#v", header, got)
+	}
+}
+
+func TestPriority_String(t *testing.T) {
+	tests := []struct {
+		p    Priority
+		want string
+	}{
+		{Default, ""},
+		{Priority{Urgency: 5}, "u=5"},
+		{Priority{Urgency: 3, Incremental: true}, "i"},
+		{Priority{Urgency: 0, Incremental: true}, "u=0, i"},
+	}
+	for _, tt := range tests {
+		if got := tt.p.String(); got != tt.want {
+			t.Errorf("%#v: want %q, got %q", tt.p, tt.want, got)
+		}
+		got, err := Parse([]string{tt.want})
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.want, err)
+			continue
+		}
+		if got != tt.p {
+			t.Errorf("%q: want %#v, got %#v", tt.want, tt.p, got)
+		}
+	}
+}
+
+func TestPriority_String_outOfRange(t *testing.T) {
+	tests := []struct {
+		p    Priority
+		want string
+	}{
+		{Priority{Urgency: 9}, "u=7"},
+		{Priority{Urgency: -1}, "u=0"},
+		{Priority{Urgency: -1, Incremental: true}, "u=0, i"},
+	}
+	for _, tt := range tests {
+		if got := tt.p.String(); got != tt.want {
+			t.Errorf("%#v: want %q, got %q", tt.p, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package priority
+
+import (
+	"github.com/shogo82148/go-sfv"
+)
+
+// Scheduler decides the order of sending the pending responses based on their priorities,
+// as recommended in RFC 9218 Section 10.
+//
+//   - The streams with lower urgency values are served first.
+//   - In the same urgency, the non-incremental streams are served one by one in the order of the stream ID.
+//   - After them, the incremental streams in the same urgency are served in round-robin.
+//
+// The zero value is an empty scheduler ready to use.
+// It is not safe for concurrent use.
+type Scheduler struct {
+	streams map[uint64]Priority
+
+	// last is the last incremental stream served in each urgency.
+	last map[int]uint64
+}
+
+// Add adds the stream with the priority.
+// If the stream already exists, its priority is replaced.
+func (s *Scheduler) Add(id uint64, p Priority) {
+	if s.streams == nil {
+		s.streams = make(map[uint64]Priority)
+	}
+	s.streams[id] = p
+}
+
+// AddDictionary adds the stream with the priority in dict, e.g. the Priority field of the request.
+// The parameters omitted in dict have the default values.
+// If the stream already exists, its priority is replaced.
+func (s *Scheduler) AddDictionary(id uint64, dict sfv.Dictionary) {
+	s.Add(id, ParseDictionary(dict))
+}
+
+// Update updates the priority of the stream with the parameters in dict,
+// e.g. the Priority Field Value of a PRIORITY_UPDATE frame.
+// The parameters omitted in dict keep the current values.
+// It reports whether the stream exists.
+func (s *Scheduler) Update(id uint64, dict sfv.Dictionary) bool {
+	p, ok := s.streams[id]
+	if !ok {
+		return false
+	}
+	s.streams[id] = p.MergeDictionary(dict)
+	return true
+}
+
+// Remove removes the stream, e.g. when the response is completed.
+func (s *Scheduler) Remove(id uint64) {
+	delete(s.streams, id)
+}
+
+// Priority returns the priority of the stream.
+func (s *Scheduler) Priority(id uint64) (Priority, bool) {
+	p, ok := s.streams[id]
+	return p, ok
+}
+
+// Len returns the number of the streams.
+func (s *Scheduler) Len() int {
+	return len(s.streams)
+}
+
+// Next returns the stream that should send the next chunk of the response.
+// The boolean result is false if there are no streams.
+func (s *Scheduler) Next() (uint64, bool) {
+	if len(s.streams) == 0 {
+		return 0, false
+	}
+
+	// find the most urgent streams.
+	urgency := MaxUrgency + 1
+	hasNonIncremental := false
+	for _, p := range s.streams {
+		u := clampUrgency(p.Urgency)
+		if u < urgency {
+			urgency = u
+			hasNonIncremental = false
+		}
+		if u == urgency && !p.Incremental {
+			hasNonIncremental = true
+		}
+	}
+
+	if hasNonIncremental {
+		// serve the non-incremental stream with the lowest ID.
+		var next uint64
+		found := false
+		for id, p := range s.streams {
+			if clampUrgency(p.Urgency) != urgency || p.Incremental {
+				continue
+			}
+			if !found || id < next {
+				next = id
+				found = true
+			}
+		}
+		return next, true
+	}
+
+	// serve the incremental stream next to the last one.
+	last, served := s.last[urgency]
+	var first, next uint64
+	foundFirst, foundNext := false, false
+	for id, p := range s.streams {
+		if clampUrgency(p.Urgency) != urgency {
+			continue
+		}
+		if !foundFirst || id < first {
+			first = id
+			foundFirst = true
+		}
+		if served && id > last && (!foundNext || id < next) {
+			next = id
+			foundNext = true
+		}
+	}
+	if !foundNext {
+		// wrap around
+		next = first
+	}
+
+	if s.last == nil {
+		s.last = make(map[int]uint64)
+	}
+	s.last[urgency] = next
+	return next, true
+}
+
+// clampUrgency limits the urgency in the valid range.
+func clampUrgency(u int) int {
+	if u < MinUrgency {
+		return MinUrgency
+	}
+	if u > MaxUrgency {
+		return MaxUrgency
+	}
+	return u
+}
@@ -0,0 +1,198 @@
+package priority
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/shogo82148/go-sfv"
+)
+
+func mustParse(t *testing.T, field string) Priority {
+	t.Helper()
+	p, err := Parse([]string{field})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// schedule calls Next n times, and returns the IDs of the streams.
+func schedule(t *testing.T, s *Scheduler, n int) []uint64 {
+	t.Helper()
+	var ids []uint64
+	for i := 0; i < n; i++ {
+		id, ok := s.Next()
+		if !ok {
+			t.Fatal("no streams")
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestScheduler_empty(t *testing.T) {
+	var s Scheduler
+	if _, ok := s.Next(); ok {
+		t.Error("want no streams")
+	}
+	if s.Update(1, nil) {
+		t.Error("want false, got true")
+	}
+}
+
+func TestScheduler_urgency(t *testing.T) {
+	var s Scheduler
+	s.Add(1, mustParse(t, "u=5"))
+	s.Add(3, mustParse(t, ""))
+	s.Add(5, mustParse(t, "u=1"))
+
+	got := schedule(t, &s, 2)
+	if want := []uint64{5, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	s.Remove(5)
+	got = schedule(t, &s, 2)
+	if want := []uint64{3, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	s.Remove(3)
+	got = schedule(t, &s, 1)
+	if want := []uint64{1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestScheduler_nonIncremental(t *testing.T) {
+	var s Scheduler
+	s.Add(9, mustParse(t, "u=2"))
+	s.Add(7, mustParse(t, "u=2"))
+	s.Add(1, mustParse(t, "u=2, i"))
+
+	// the non-incremental streams are served one by one in order of the stream ID.
+	got := schedule(t, &s, 2)
+	if want := []uint64{7, 7}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+	s.Remove(7)
+	got = schedule(t, &s, 2)
+	if want := []uint64{9, 9}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+	s.Remove(9)
+	got = schedule(t, &s, 2)
+	if want := []uint64{1, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestScheduler_incremental(t *testing.T) {
+	var s Scheduler
+	s.Add(4, mustParse(t, "i"))
+	s.Add(0, mustParse(t, "i"))
+	s.Add(8, mustParse(t, "i"))
+	s.Add(12, mustParse(t, "u=4, i"))
+
+	// the incremental streams are served in round-robin.
+	got := schedule(t, &s, 5)
+	if want := []uint64{0, 4, 8, 0, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// a new stream joins the round.
+	s.Add(6, mustParse(t, "i"))
+	got = schedule(t, &s, 4)
+	if want := []uint64{6, 8, 0, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// the last served stream is completed.
+	s.Remove(4)
+	got = schedule(t, &s, 3)
+	if want := []uint64{6, 8, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestScheduler_Update(t *testing.T) {
+	var s Scheduler
+	s.Add(0, mustParse(t, "u=3, i"))
+	s.Add(4, mustParse(t, "u=3, i"))
+	s.Add(8, mustParse(t, "u=5"))
+
+	got := schedule(t, &s, 3)
+	if want := []uint64{0, 4, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// PRIORITY_UPDATE frame raises the priority of the stream 8.
+	dict, err := sfv.DecodeDictionary([]string{"u=0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Update(8, dict) {
+		t.Fatal("want true, got false")
+	}
+	if p, _ := s.Priority(8); p != (Priority{Urgency: 0}) {
+		t.Errorf("want u=0, got %v", p)
+	}
+	got = schedule(t, &s, 2)
+	if want := []uint64{8, 8}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// make it incremental, it is interleaved with nothing in the urgency 0.
+	dict, err = sfv.DecodeDictionary([]string{"i"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Update(8, dict)
+	got = schedule(t, &s, 2)
+	if want := []uint64{8, 8}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// lower the priority again, and the round-robin of the urgency 3 continues.
+	dict, err = sfv.DecodeDictionary([]string{"u=3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Update(8, dict)
+	got = schedule(t, &s, 4)
+	if want := []uint64{4, 8, 0, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+	if s.Len() != 3 {
+		t.Errorf("want 3, got %d", s.Len())
+	}
+}
+
+func TestScheduler_AddDictionary(t *testing.T) {
+	var s Scheduler
+
+	// the Priority field of the request.
+	dict, err := sfv.DecodeDictionary([]string{"u=2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.AddDictionary(0, dict)
+	s.AddDictionary(4, nil)
+	if p, _ := s.Priority(0); p != (Priority{Urgency: 2}) {
+		t.Errorf("want u=2, got %v", p)
+	}
+	if p, _ := s.Priority(4); p != Default {
+		t.Errorf("want the default priority, got %v", p)
+	}
+
+	// PRIORITY_UPDATE frame goes through the same path.
+	dict, err = sfv.DecodeDictionary([]string{"u=1, i"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Update(4, dict)
+	got := schedule(t, &s, 2)
+	if want := []uint64{4, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,22 @@
+//go:build !plan9 && !windows
+
+package proxystatus
+
+import (
+	"errors"
+	"syscall"
+)
+
+// classifyErrno returns the error type of the errors of the system calls,
+// or an empty string if err is not recognized.
+func classifyErrno(err error) ErrorType {
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ConnectionRefused
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.ECONNABORTED), errors.Is(err, syscall.EPIPE):
+		return ConnectionTerminated
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return DestinationIPUnroutable
+	}
+	return ""
+}
@@ -0,0 +1,9 @@
+//go:build plan9
+
+package proxystatus
+
+// classifyErrno returns an empty string,
+// because Plan 9 reports the errors of the system calls as strings.
+func classifyErrno(err error) ErrorType {
+	return ""
+}
@@ -0,0 +1,35 @@
+//go:build !plan9 && !windows
+
+package proxystatus
+
+import (
+	"net"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestErrorTypeOf_errno(t *testing.T) {
+	tests := []struct {
+		err  error
+		want ErrorType
+	}{
+		{
+			err:  &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)},
+			want: ConnectionRefused,
+		},
+		{
+			err:  &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)},
+			want: ConnectionTerminated,
+		},
+		{
+			err:  &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.EHOSTUNREACH)},
+			want: DestinationIPUnroutable,
+		},
+	}
+	for _, tt := range tests {
+		if got := ErrorTypeOf(tt.err); got != tt.want {
+			t.Errorf("%v: want %q, got %q", tt.err, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+//go:build windows
+
+package proxystatus
+
+import (
+	"errors"
+	"syscall"
+)
+
+// The Windows Sockets error codes.
+// The syscall package defines only some of them, and its ECONNREFUSED and so on never match the errors of the sockets.
+const (
+	wsaENETUNREACH  syscall.Errno = 10051
+	wsaECONNABORTED syscall.Errno = 10053
+	wsaECONNRESET   syscall.Errno = 10054
+	wsaECONNREFUSED syscall.Errno = 10061
+	wsaEHOSTUNREACH syscall.Errno = 10065
+)
+
+// classifyErrno returns the error type of the errors of the system calls,
+// or an empty string if err is not recognized.
+func classifyErrno(err error) ErrorType {
+	switch {
+	case errors.Is(err, wsaECONNREFUSED):
+		return ConnectionRefused
+	case errors.Is(err, wsaECONNRESET), errors.Is(err, wsaECONNABORTED), errors.Is(err, syscall.ERROR_BROKEN_PIPE):
+		return ConnectionTerminated
+	case errors.Is(err, wsaEHOSTUNREACH), errors.Is(err, wsaENETUNREACH):
+		return DestinationIPUnroutable
+	}
+	return ""
+}
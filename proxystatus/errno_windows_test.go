@@ -0,0 +1,34 @@
+//go:build windows
+
+package proxystatus
+
+import (
+	"net"
+	"os"
+	"testing"
+)
+
+func TestErrorTypeOf_errno(t *testing.T) {
+	tests := []struct {
+		err  error
+		want ErrorType
+	}{
+		{
+			err:  &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", wsaECONNREFUSED)},
+			want: ConnectionRefused,
+		},
+		{
+			err:  &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", wsaECONNRESET)},
+			want: ConnectionTerminated,
+		},
+		{
+			err:  &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", wsaEHOSTUNREACH)},
+			want: DestinationIPUnroutable,
+		},
+	}
+	for _, tt := range tests {
+		if got := ErrorTypeOf(tt.err); got != tt.want {
+			t.Errorf("%v: want %q, got %q", tt.err, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package proxystatus
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+)
+
+// ErrorType is a proxy error type registered in RFC 9209 Section 2.3.
+type ErrorType string
+
+// The proxy error types registered in RFC 9209 Section 2.3.
+const (
+	DNSTimeout                     ErrorType = "dns_timeout"
+	DNSError                       ErrorType = "dns_error"
+	DestinationNotFound            ErrorType = "destination_not_found"
+	DestinationUnavailable         ErrorType = "destination_unavailable"
+	DestinationIPProhibited        ErrorType = "destination_ip_prohibited"
+	DestinationIPUnroutable        ErrorType = "destination_ip_unroutable"
+	ConnectionRefused              ErrorType = "connection_refused"
+	ConnectionTerminated           ErrorType = "connection_terminated"
+	ConnectionTimeout              ErrorType = "connection_timeout"
+	ConnectionReadTimeout          ErrorType = "connection_read_timeout"
+	ConnectionWriteTimeout         ErrorType = "connection_write_timeout"
+	ConnectionLimitReached         ErrorType = "connection_limit_reached"
+	TLSProtocolError               ErrorType = "tls_protocol_error"
+	TLSCertificateError            ErrorType = "tls_certificate_error"
+	TLSAlertReceived               ErrorType = "tls_alert_received"
+	HTTPRequestError               ErrorType = "http_request_error"
+	HTTPRequestDenied              ErrorType = "http_request_denied"
+	HTTPResponseIncomplete         ErrorType = "http_response_incomplete"
+	HTTPResponseHeaderSectionSize  ErrorType = "http_response_header_section_size"
+	HTTPResponseHeaderSize         ErrorType = "http_response_header_size"
+	HTTPResponseBodySize           ErrorType = "http_response_body_size"
+	HTTPResponseTrailerSectionSize ErrorType = "http_response_trailer_section_size"
+	HTTPResponseTrailerSize        ErrorType = "http_response_trailer_size"
+	HTTPResponseTransferCoding     ErrorType = "http_response_transfer_coding"
+	HTTPResponseContentCoding      ErrorType = "http_response_content_coding"
+	HTTPResponseTimeout            ErrorType = "http_response_timeout"
+	HTTPUpgradeFailed              ErrorType = "http_upgrade_failed"
+	HTTPProtocolError              ErrorType = "http_protocol_error"
+	ProxyInternalResponse          ErrorType = "proxy_internal_response"
+	ProxyInternalError             ErrorType = "proxy_internal_error"
+	ProxyConfigurationError        ErrorType = "proxy_configuration_error"
+	ProxyLoopDetected              ErrorType = "proxy_loop_detected"
+)
+
+// Valid reports whether t is registered in RFC 9209.
+func (t ErrorType) Valid() bool {
+	switch t {
+	case DNSTimeout, DNSError,
+		DestinationNotFound, DestinationUnavailable, DestinationIPProhibited, DestinationIPUnroutable,
+		ConnectionRefused, ConnectionTerminated, ConnectionTimeout,
+		ConnectionReadTimeout, ConnectionWriteTimeout, ConnectionLimitReached,
+		TLSProtocolError, TLSCertificateError, TLSAlertReceived,
+		HTTPRequestError, HTTPRequestDenied, HTTPResponseIncomplete,
+		HTTPResponseHeaderSectionSize, HTTPResponseHeaderSize, HTTPResponseBodySize,
+		HTTPResponseTrailerSectionSize, HTTPResponseTrailerSize,
+		HTTPResponseTransferCoding, HTTPResponseContentCoding,
+		HTTPResponseTimeout, HTTPUpgradeFailed, HTTPProtocolError,
+		ProxyInternalResponse, ProxyInternalError, ProxyConfigurationError, ProxyLoopDetected:
+		return true
+	}
+	return false
+}
+
+// ErrorTypeOf returns the error type that describes err,
+// that is typically returned by http.Transport or net.Dialer.
+// It returns ProxyInternalError if err is not recognized.
+func ErrorTypeOf(err error) ErrorType {
+	t, _ := classify(err)
+	return t
+}
+
+// WithError returns e with the error type and its extra parameters that describe err.
+// Details is not set, because the message of err may contain sensitive information.
+func (e Entry) WithError(err error) Entry {
+	e.Error, e.RCode = classify(err)
+	return e
+}
+
+// classify returns the error type and the rcode of err.
+func classify(err error) (ErrorType, string) {
+	// DNS errors
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		if dnsErr.IsTimeout {
+			return DNSTimeout, ""
+		}
+		if dnsErr.IsNotFound {
+			return DNSError, "NXDOMAIN"
+		}
+		return DNSError, ""
+	}
+
+	// TLS errors
+	var (
+		unknownAuthorityErr x509.UnknownAuthorityError
+		hostnameErr         x509.HostnameError
+		certInvalidErr      x509.CertificateInvalidError
+		recordHeaderErr     tls.RecordHeaderError
+	)
+	switch {
+	case errors.As(err, &unknownAuthorityErr), errors.As(err, &hostnameErr), errors.As(err, &certInvalidErr):
+		return TLSCertificateError, ""
+	case errors.As(err, &recordHeaderErr):
+		return TLSProtocolError, ""
+	}
+
+	// timeouts of the connection
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Timeout() {
+		switch opErr.Op {
+		case "dial":
+			return ConnectionTimeout, ""
+		case "read":
+			return ConnectionReadTimeout, ""
+		case "write":
+			return ConnectionWriteTimeout, ""
+		}
+	}
+
+	// errors of the system calls
+	if t := classifyErrno(err); t != "" {
+		return t, ""
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return HTTPResponseTimeout, ""
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return HTTPResponseTimeout, ""
+	}
+	return ProxyInternalError, ""
+}
@@ -0,0 +1,256 @@
+// Package proxystatus implements the Proxy-Status field defined in RFC 9209 The Proxy-Status HTTP Response Header Field.
+package proxystatus
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shogo82148/go-sfv"
+	"github.com/shogo82148/go-sfv/internal/fieldutil"
+)
+
+// FieldName is the name of the Proxy-Status field.
+const FieldName = "Proxy-Status"
+
+// Entry is a member of the Proxy-Status field, that describes how an intermediary handled the response.
+type Entry struct {
+	// Proxy identifies the intermediary.
+	// It is serialized as a Token if possible, otherwise as a String.
+	Proxy string
+
+	// Error is the error parameter, or empty if it is omitted.
+	// Parsing keeps unregistered types, but Validate rejects them.
+	Error ErrorType
+
+	// NextHop is the next-hop parameter, or empty if it is omitted.
+	// It is serialized as a Token if possible, otherwise as a String.
+	NextHop string
+
+	// NextProtocol is the next-protocol parameter, that is an ALPN protocol identifier, or empty if it is omitted.
+	// It is serialized as a Token if possible, otherwise as a Byte Sequence.
+	NextProtocol string
+
+	// ReceivedStatus is the received-status parameter, or 0 if it is omitted.
+	ReceivedStatus int
+
+	// Details is the details parameter, or empty if it is omitted.
+	Details string
+
+	// RCode is the rcode parameter of the dns_error type, or empty if it is omitted.
+	RCode string
+
+	// InfoCode is the info-code parameter of the dns_error type, or nil if it is omitted.
+	InfoCode *int64
+
+	// AlertID is the alert-id parameter of the tls_alert_received type, or nil if it is omitted.
+	AlertID *int64
+
+	// Extra is the other parameters, e.g. the extra parameters of the other error types.
+	Extra sfv.Parameters
+}
+
+// ProxyStatus is the list of the entries, in the order of the intermediaries from the origin server to the user agent.
+type ProxyStatus []Entry
+
+// Parse parses the Proxy-Status fields.
+func Parse(fields []string) (ProxyStatus, error) {
+	list, err := sfv.DecodeList(fields)
+	if err != nil {
+		return nil, err
+	}
+	return ParseList(list)
+}
+
+// ParseList converts list into the entries.
+func ParseList(list sfv.List) (ProxyStatus, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+	ps := make(ProxyStatus, 0, len(list))
+	for _, item := range list {
+		e, err := ParseItem(item)
+		if err != nil {
+			return nil, err
+		}
+		ps = append(ps, e)
+	}
+	return ps, nil
+}
+
+// ParseItem converts item into the entry.
+// The known parameters with invalid values are ignored,
+// so that one malformed member doesn't discard the others received from upstream.
+func ParseItem(item sfv.Item) (Entry, error) {
+	var e Entry
+	var ok bool
+	if e.Proxy, ok = fieldutil.StringOf(item.Value); !ok {
+		return Entry{}, fmt.Errorf("proxystatus: the intermediary identifier must be a token or a string, got %T", item.Value)
+	}
+
+	for _, param := range item.Parameters {
+		prev := e
+		var ok bool
+		switch param.Key {
+		case "error":
+			var v sfv.Token
+			v, ok = param.Value.(sfv.Token)
+			e.Error = ErrorType(v)
+		case "next-hop":
+			e.NextHop, ok = fieldutil.StringOf(param.Value)
+		case "next-protocol":
+			switch v := param.Value.(type) {
+			case sfv.Token:
+				e.NextProtocol, ok = string(v), true
+			case []byte:
+				e.NextProtocol, ok = string(v), true
+			}
+		case "received-status":
+			var v int64
+			v, ok = param.Value.(int64)
+			e.ReceivedStatus = int(v)
+			ok = ok && v >= 100 && v <= 999
+		case "details":
+			e.Details, ok = param.Value.(string)
+		case "rcode":
+			e.RCode, ok = param.Value.(string)
+		case "info-code":
+			var v int64
+			v, ok = param.Value.(int64)
+			e.InfoCode = &v
+		case "alert-id":
+			var v int64
+			v, ok = param.Value.(int64)
+			e.AlertID = &v
+		default:
+			e.Extra = append(e.Extra, param)
+			ok = true
+		}
+		if !ok {
+			// revert the partial assignment of the invalid parameter.
+			e = prev
+		}
+	}
+	return e, nil
+}
+
+// Validate checks that e can be serialized as a member of the Proxy-Status field.
+func (e Entry) Validate() error {
+	if e.Proxy == "" {
+		return errors.New("proxystatus: the intermediary identifier is empty")
+	}
+	if e.Error != "" && !e.Error.Valid() {
+		return fmt.Errorf("proxystatus: %q has unregistered error type %q", e.Proxy, e.Error)
+	}
+	if e.ReceivedStatus != 0 && (e.ReceivedStatus < 100 || e.ReceivedStatus > 999) {
+		return fmt.Errorf("proxystatus: %q has invalid received-status %d", e.Proxy, e.ReceivedStatus)
+	}
+	if (e.RCode != "" || e.InfoCode != nil) && e.Error != DNSError {
+		return fmt.Errorf("proxystatus: %q has rcode or info-code without dns_error", e.Proxy)
+	}
+	if e.AlertID != nil && e.Error != TLSAlertReceived {
+		return fmt.Errorf("proxystatus: %q has alert-id without tls_alert_received", e.Proxy)
+	}
+	if key, ok := fieldutil.ReservedParam(e.Extra, "error", "next-hop", "next-protocol", "received-status", "details", "rcode", "info-code", "alert-id"); ok {
+		return fmt.Errorf("proxystatus: %q has the parameter %q in Extra", e.Proxy, key)
+	}
+	return nil
+}
+
+// Item converts e into the member of the Proxy-Status field.
+func (e Entry) Item() (sfv.Item, error) {
+	if err := e.Validate(); err != nil {
+		return sfv.Item{}, err
+	}
+
+	item := sfv.Item{
+		Value: fieldutil.TokenOrString(e.Proxy),
+	}
+	add := func(key string, value sfv.Value) {
+		item.Parameters = append(item.Parameters, sfv.Parameter{Key: key, Value: value})
+	}
+	if e.Error != "" {
+		add("error", sfv.Token(e.Error))
+	}
+	if e.NextHop != "" {
+		add("next-hop", fieldutil.TokenOrString(e.NextHop))
+	}
+	if e.NextProtocol != "" {
+		if t := sfv.Token(e.NextProtocol); t.Valid() {
+			add("next-protocol", t)
+		} else {
+			add("next-protocol", []byte(e.NextProtocol))
+		}
+	}
+	if e.ReceivedStatus != 0 {
+		add("received-status", int64(e.ReceivedStatus))
+	}
+	if e.Details != "" {
+		add("details", e.Details)
+	}
+	if e.RCode != "" {
+		add("rcode", e.RCode)
+	}
+	if e.InfoCode != nil {
+		add("info-code", *e.InfoCode)
+	}
+	if e.AlertID != nil {
+		add("alert-id", *e.AlertID)
+	}
+	item.Parameters = append(item.Parameters, e.Extra...)
+
+	if err := sfv.ValidateItem(item); err != nil {
+		return sfv.Item{}, err
+	}
+	return item, nil
+}
+
+// List converts ps into the Proxy-Status field.
+func (ps ProxyStatus) List() (sfv.List, error) {
+	list := make(sfv.List, 0, len(ps))
+	for _, e := range ps {
+		item, err := e.Item()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, item)
+	}
+	return list, nil
+}
+
+// Encode serializes ps.
+func (ps ProxyStatus) Encode() (string, error) {
+	list, err := ps.List()
+	if err != nil {
+		return "", err
+	}
+	return sfv.EncodeList(list)
+}
+
+// Lookup returns the first entry of the intermediary.
+func (ps ProxyStatus) Lookup(proxy string) (Entry, bool) {
+	for _, e := range ps {
+		if e.Proxy == proxy {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Append appends e to the received Proxy-Status fields, and returns the new field value.
+// The received members are kept byte-for-byte identical.
+func Append(fields []string, e Entry) (string, error) {
+	item, err := e.Item()
+	if err != nil {
+		return "", err
+	}
+	return fieldutil.AppendListMember(fields, item)
+}
+
+// AppendHeader appends e to the Proxy-Status field in h.
+func AppendHeader(h sfv.Header, e Entry) error {
+	item, err := e.Item()
+	if err != nil {
+		return err
+	}
+	return fieldutil.AppendHeader(h, FieldName, item)
+}
@@ -0,0 +1,234 @@
+package proxystatus
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/shogo82148/go-sfv"
+)
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func TestParse(t *testing.T) {
+	// the examples in RFC 9209.
+	tests := []struct {
+		field string
+		want  ProxyStatus
+	}{
+		{
+			`FooProxy; error=http_request_error; details="Malformed request"`,
+			ProxyStatus{{Proxy: "FooProxy", Error: HTTPRequestError, Details: "Malformed request"}},
+		},
+		{
+			`ExampleCDN; error=dns_error; rcode="NXDOMAIN"; info-code=12`,
+			ProxyStatus{{Proxy: "ExampleCDN", Error: DNSError, RCode: "NXDOMAIN", InfoCode: int64Ptr(12)}},
+		},
+		{
+			`ExampleCDN; error=tls_alert_received; alert-id=40; alert-message=handshake_failure`,
+			ProxyStatus{{
+				Proxy:   "ExampleCDN",
+				Error:   TLSAlertReceived,
+				AlertID: int64Ptr(40),
+				Extra:   sfv.Parameters{{Key: "alert-message", Value: sfv.Token("handshake_failure")}},
+			}},
+		},
+		{
+			`ExampleCDN, SomeReverseProxy; error=destination_unavailable; next-hop=backend.example.org; next-protocol=h2; received-status=503`,
+			ProxyStatus{
+				{Proxy: "ExampleCDN"},
+				{
+					Proxy:          "SomeReverseProxy",
+					Error:          DestinationUnavailable,
+					NextHop:        "backend.example.org",
+					NextProtocol:   "h2",
+					ReceivedStatus: 503,
+				},
+			},
+		},
+		{
+			`"Example Proxy"; next-hop="192.0.2.1:8080"; next-protocol=:aDM=:`,
+			ProxyStatus{{Proxy: "Example Proxy", NextHop: "192.0.2.1:8080", NextProtocol: "h3"}},
+		},
+	}
+	for _, tt := range tests {
+		got, err := Parse([]string{tt.field})
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.field, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: want %#v, got %#v", tt.field, tt.want, got)
+		}
+	}
+}
+
+func TestParse_invalid(t *testing.T) {
+	for _, field := range []string{
+		`FooProxy,`,
+		`1`,
+	} {
+		if _, err := Parse([]string{field}); err == nil {
+			t.Errorf("%q: want error, but not", field)
+		}
+	}
+}
+
+func TestParse_invalidParameter(t *testing.T) {
+	const field = `FooProxy; error="dns_error"; received-status=5000; details=foo; next-protocol="h2"; info-code=1.5, BarProxy; error=dns_error; rcode=NXDOMAIN; received-status=503`
+	ps, err := Parse([]string{field})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ProxyStatus{
+		{Proxy: "FooProxy"},
+		{Proxy: "BarProxy", Error: DNSError, ReceivedStatus: 503},
+	}
+	if !reflect.DeepEqual(ps, want) {
+		t.Errorf("want %#v, got %#v", want, ps)
+	}
+}
+
+func TestProxyStatus_Encode(t *testing.T) {
+	ps := ProxyStatus{
+		{Proxy: "ExampleCDN", Error: DNSError, RCode: "SERVFAIL", InfoCode: int64Ptr(23)},
+		{Proxy: "Example Proxy", Error: ConnectionTimeout, NextHop: "192.0.2.1:8080", NextProtocol: "h2", Details: "timed out"},
+		{Proxy: "Other", NextProtocol: "h3 draft", ReceivedStatus: 200},
+	}
+	got, err := ps.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `ExampleCDN;error=dns_error;rcode="SERVFAIL";info-code=23, ` +
+		`"Example Proxy";error=connection_timeout;next-hop="192.0.2.1:8080";next-protocol=h2;details="timed out", ` +
+		`Other;next-protocol=:aDMgZHJhZnQ=:;received-status=200`
+	if got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	parsed, err := Parse([]string{got})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, ps) {
+		t.Errorf("want %#v, got %#v", ps, parsed)
+	}
+
+	if e, ok := parsed.Lookup("Example Proxy"); !ok || e.Error != ConnectionTimeout {
+		t.Errorf("unexpected entry: %#v", e)
+	}
+	if _, ok := parsed.Lookup("missing"); ok {
+		t.Error("want not found")
+	}
+}
+
+func TestEntry_Validate(t *testing.T) {
+	tests := []Entry{
+		{},
+		{Proxy: "p", Error: "unknown_error"},
+		{Proxy: "p", ReceivedStatus: 42},
+		{Proxy: "p", Error: ConnectionRefused, RCode: "NXDOMAIN"},
+		{Proxy: "p", Error: TLSProtocolError, AlertID: int64Ptr(40)},
+		{Proxy: "p", Extra: sfv.Parameters{{Key: "error", Value: sfv.Token("dns_error")}}},
+	}
+	for _, e := range tests {
+		if err := e.Validate(); err == nil {
+			t.Errorf("%#v: want error, but not", e)
+		}
+		if _, err := e.Item(); err == nil {
+			t.Errorf("%#v: want error, but not", e)
+		}
+	}
+}
+
+func TestAppendHeader(t *testing.T) {
+	h := make(http.Header)
+	h.Add("Proxy-Status", "origin-proxy;received-status=200")
+	if err := AppendHeader(h, Entry{Proxy: "cdn", Error: HTTPResponseTimeout}); err != nil {
+		t.Fatal(err)
+	}
+	got := h.Values("Proxy-Status")
+	want := []string{"origin-proxy;received-status=200, cdn;error=http_response_timeout"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	if err := AppendHeader(h, Entry{Proxy: "cdn", Error: "unknown"}); err == nil {
+		t.Error("want error, but not")
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestErrorTypeOf(t *testing.T) {
+	tests := []struct {
+		err   error
+		want  ErrorType
+		rcode string
+	}{
+		{
+			err:  &net.DNSError{Err: "timeout", Name: "example.com", IsTimeout: true},
+			want: DNSTimeout,
+		},
+		{
+			err:   &net.DNSError{Err: "no such host", Name: "example.com", IsNotFound: true},
+			want:  DNSError,
+			rcode: "NXDOMAIN",
+		},
+		{
+			err:  &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "server misbehaving", Name: "example.com"}},
+			want: DNSError,
+		},
+		{
+			err:  &net.OpError{Op: "dial", Net: "tcp", Err: timeoutError{}},
+			want: ConnectionTimeout,
+		},
+		{
+			err:  &net.OpError{Op: "read", Net: "tcp", Err: timeoutError{}},
+			want: ConnectionReadTimeout,
+		},
+		{
+			err:  &net.OpError{Op: "write", Net: "tcp", Err: timeoutError{}},
+			want: ConnectionWriteTimeout,
+		},
+		{
+			err:  fmt.Errorf("tls: %w", x509.UnknownAuthorityError{}),
+			want: TLSCertificateError,
+		},
+		{
+			err:  x509.HostnameError{Host: "example.com", Certificate: &x509.Certificate{}},
+			want: TLSCertificateError,
+		},
+		{
+			err:  fmt.Errorf("roundtrip: %w", context.DeadlineExceeded),
+			want: HTTPResponseTimeout,
+		},
+		{
+			err:  errors.New("something wrong"),
+			want: ProxyInternalError,
+		},
+	}
+	for _, tt := range tests {
+		if got := ErrorTypeOf(tt.err); got != tt.want {
+			t.Errorf("%v: want %q, got %q", tt.err, tt.want, got)
+		}
+		e := Entry{Proxy: "p"}.WithError(tt.err)
+		if e.Error != tt.want || e.RCode != tt.rcode {
+			t.Errorf("%v: want %q, %q, got %q, %q", tt.err, tt.want, tt.rcode, e.Error, e.RCode)
+		}
+		if err := e.Validate(); err != nil {
+			t.Errorf("%v: unexpected error: %v", tt.err, err)
+		}
+	}
+}
@@ -0,0 +1,342 @@
+package sfv
+
+import (
+	"encoding/base64"
+	"errors"
+	"unicode/utf8"
+)
+
+// The skip functions validate the input in the same way as the decode functions,
+// but don't build the values in order to avoid allocations.
+
+// skipBareItem skips a bare item according to RFC 9651 Section 4.2.3.1,
+// and returns its kind.
+func (s *decodeState) skipBareItem() (BareItemKind, error) {
+	ch := s.peek()
+	switch {
+	case ch == '-' || isDigit(ch):
+		// an Integer or Decimal
+		return s.skipIntegerOrDecimal()
+
+	case ch == '"':
+		// a String
+		return BareItemString, s.skipString()
+
+	case ch == '*' || (lower(ch) >= 'a' && lower(ch) <= 'z'):
+		// a Token
+		return BareItemToken, s.skipToken()
+
+	case ch == ':':
+		// a Byte Sequence
+		return BareItemByteSequence, s.skipByteSequence()
+
+	case ch == '?':
+		// a Boolean
+		_, err := s.decodeBoolean()
+		return BareItemBoolean, err
+
+	case ch == '@':
+		// a Date
+		return BareItemDate, s.skipDate()
+
+	case ch == '%':
+		// a Display String
+		return BareItemDisplayString, s.skipDisplayString()
+	}
+	return 0, s.errUnexpectedCharacter()
+}
+
+// skipIntegerOrDecimal skips an Integer or Decimal according to RFC 9651 Section 4.2.4.
+func (s *decodeState) skipIntegerOrDecimal() (BareItemKind, error) {
+	if s.peek() == '-' {
+		s.next() // skip '-'
+		if !isDigit(s.peek()) {
+			return 0, s.errUnexpectedCharacter()
+		}
+	}
+
+	cnt := 0
+	for isDigit(s.peek()) {
+		s.next()
+		cnt++
+		if cnt > 15 {
+			return 0, errors.New("sfv: integer overflow")
+		}
+	}
+	if s.peek() != '.' {
+		// it is an Integer
+		return BareItemInteger, nil
+	}
+	s.next() // skip '.'
+
+	// it might be a Decimal
+	if cnt > 12 {
+		return 0, errors.New("sfv: decimal overflow")
+	}
+	if !isDigit(s.peek()) {
+		// fractional part MUST NOT be empty.
+		return 0, s.errUnexpectedCharacter()
+	}
+	for i := 0; isDigit(s.peek()); i++ {
+		if i >= 3 {
+			return 0, errors.New("sfv: decimal has too long fractional part")
+		}
+		s.next()
+	}
+	return BareItemDecimal, nil
+}
+
+// skipString skips a String according to RFC 9651 Section 4.2.5.
+func (s *decodeState) skipString() error {
+	if ch := s.peek(); ch != '"' {
+		return s.errUnexpectedCharacter()
+	}
+	s.next() // skip '"'
+	for {
+		ch := s.peek()
+		switch {
+		case ch == '\\':
+			s.next() // skip '\\'
+			switch s.peek() {
+			case '\\', '"':
+				s.next()
+			default:
+				return s.errUnexpectedCharacter()
+			}
+		case ch == '"':
+			// the end of a String
+			s.next() // skip '"'
+			return nil
+		case ch >= 0x20 && ch < 0x7f:
+			s.next()
+		default:
+			return s.errUnexpectedCharacter()
+		}
+	}
+}
+
+// skipToken skips a Token according to RFC 9651 Section 4.2.6.
+func (s *decodeState) skipToken() error {
+	s.next() // skip the first character
+	for {
+		ch := s.peek()
+		if ch == endOfInput || !validTokenChars[ch] {
+			return nil
+		}
+		s.next()
+	}
+}
+
+// skipByteSequence skips a Byte Sequence according to RFC 9651 Section 4.2.7.
+func (s *decodeState) skipByteSequence() error {
+	if ch := s.peek(); ch != ':' {
+		return s.errUnexpectedCharacter()
+	}
+	s.next() // skip ':'
+	s.buf.Reset()
+	for {
+		ch := s.peek()
+		switch {
+		case ch == endOfInput:
+			return s.errUnexpectedCharacter()
+		case ch == ':':
+			// the end of a Binary
+			s.next() // skip ':'
+
+			// add missing "=" padding
+			switch s.buf.Len() % 4 {
+			case 0:
+			case 1:
+				s.buf.WriteByte('=')
+				fallthrough
+			case 2:
+				s.buf.WriteByte('=')
+				fallthrough
+			case 3:
+				s.buf.WriteByte('=')
+			}
+
+			// decode into the reusable buffer to check the padding.
+			enc := base64.StdEncoding
+			if n := enc.DecodedLen(s.buf.Len()); cap(s.scratch) < n {
+				s.scratch = make([]byte, n)
+			}
+			_, err := enc.Decode(s.scratch[:cap(s.scratch)], s.buf.Bytes())
+			return err
+		case validBase64Chars[ch]:
+			s.next()
+			s.buf.WriteByte(byte(ch))
+		default:
+			return s.errUnexpectedCharacter()
+		}
+	}
+}
+
+// skipDate skips a Date according to RFC 9651 Section 4.2.9.
+func (s *decodeState) skipDate() error {
+	if ch := s.peek(); ch != '@' {
+		return s.errUnexpectedCharacter()
+	}
+	s.next() // skip '@'
+	if s.peek() == '-' {
+		s.next() // skip '-'
+	}
+	if !isDigit(s.peek()) {
+		return s.errUnexpectedCharacter()
+	}
+
+	cnt := 0
+	for isDigit(s.peek()) {
+		s.next()
+		cnt++
+		if cnt > 15 {
+			return errors.New("sfv: integer overflow")
+		}
+	}
+	if s.peek() == '.' {
+		// a Date must not a Decimal.
+		return s.errUnexpectedCharacter()
+	}
+	return nil
+}
+
+// skipDisplayString skips a Display String according to RFC 9651 Section 4.2.10.
+func (s *decodeState) skipDisplayString() error {
+	if ch := s.peek(); ch != '%' {
+		return s.errUnexpectedCharacter()
+	}
+	s.next() // skip '%'
+
+	// next character must be DQUOTE.
+	if ch := s.peek(); ch != '"' {
+		return s.errUnexpectedCharacter()
+	}
+	s.next() // skip '"'
+
+	s.buf.Reset()
+	for {
+		ch := s.peek()
+		if ch <= 0x1f || ch >= 0x7f {
+			return s.errUnexpectedCharacter()
+		}
+		s.next()
+
+		if ch == '%' {
+			// %-encoded character
+			digit1 := s.peek()
+			if !isHexDigit(digit1) {
+				return s.errUnexpectedCharacter()
+			}
+			s.next()
+			digit2 := s.peek()
+			if !isHexDigit(digit2) {
+				return s.errUnexpectedCharacter()
+			}
+			s.next()
+			s.buf.WriteByte(hex(digit1)<<4 | hex(digit2))
+		} else if ch == '"' {
+			// the end of a Display String
+			if !utf8.Valid(s.buf.Bytes()) {
+				return errors.New("sfv: invalid UTF-8 sequence")
+			}
+			return nil
+		} else {
+			s.buf.WriteByte(byte(ch))
+		}
+	}
+}
+
+// skipKey skips a key according to RFC 9651 Section 4.2.3.3.
+func (s *decodeState) skipKey() error {
+	ch := s.peek()
+	if (ch < 'a' || ch > 'z') && ch != '*' {
+		return s.errUnexpectedCharacter()
+	}
+	s.next()
+	for {
+		ch := s.peek()
+		if ch == endOfInput || !validKeyChars[ch] {
+			return nil
+		}
+		s.next()
+	}
+}
+
+// skipParameters skips parameters according to RFC 9651 Section 4.2.3.2.
+func (s *decodeState) skipParameters() error {
+	for s.peek() == ';' {
+		s.next() // skip ';'
+		s.skipSPs()
+		if err := s.skipKey(); err != nil {
+			return err
+		}
+		if s.peek() == '=' {
+			s.next() // skip '='
+			if _, err := s.skipBareItem(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// skipItem skips an item according to RFC 9651 Section 4.2.3.
+func (s *decodeState) skipItem() error {
+	if _, err := s.skipBareItem(); err != nil {
+		return err
+	}
+	return s.skipParameters()
+}
+
+// skipItemOrInnerList skips an item or an inner list according to RFC 9651 Section 4.2.1.1.
+func (s *decodeState) skipItemOrInnerList() error {
+	if s.peek() != '(' {
+		return s.skipItem()
+	}
+	s.next() // skip '('
+	for {
+		s.skipSPs()
+		if s.peek() == ')' {
+			s.next() // skip ')'
+			break
+		}
+		if err := s.skipItem(); err != nil {
+			return err
+		}
+		if ch := s.peek(); ch != ' ' && ch != ')' {
+			return s.errUnexpectedCharacter()
+		}
+	}
+	return s.skipParameters()
+}
+
+// skipMemberLoosely skips a member of a list or a dictionary without validation.
+// It stops at the comma that separates the members.
+func (s *decodeState) skipMemberLoosely() {
+	depth := 0
+	for {
+		switch s.peek() {
+		case endOfInput:
+			return
+		case ',':
+			if depth <= 0 {
+				return
+			}
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case '"':
+			// skip the String or the Display String,
+			// it may contain commas and parentheses.
+			s.next() // skip '"'
+			for ch := s.peek(); ch != '"' && ch != endOfInput; ch = s.peek() {
+				if ch == '\\' {
+					s.next() // skip '\\'
+				}
+				s.next()
+			}
+		}
+		s.next()
+	}
+}
@@ -0,0 +1,228 @@
+package sfv
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// selectorStep is a step of a path.
+type selectorStep struct {
+	// param is true if the step selects parameters.
+	param bool
+
+	// key is the key of the member or the parameter.
+	// It is empty if the step selects by index.
+	key string
+
+	// index is the index of the member or the parameter.
+	// It is -1 for the wildcard.
+	index int
+}
+
+// parsePath parses the path for Select.
+func parsePath(path string) ([]selectorStep, error) {
+	var steps []selectorStep
+	i := 0
+	for i < len(path) {
+		var step selectorStep
+		if path[i] == ';' {
+			step.param = true
+			i++
+		}
+
+		if i < len(path) && path[i] == '[' {
+			// an index or the wildcard
+			end := i + 1
+			for end < len(path) && path[end] != ']' {
+				end++
+			}
+			if end >= len(path) {
+				return nil, fmt.Errorf("sfv: invalid path %q: missing ']'", path)
+			}
+			index := path[i+1 : end]
+			if index == "*" {
+				step.index = -1
+			} else {
+				n, err := strconv.Atoi(index)
+				if err != nil || n < 0 || index[0] == '+' {
+					return nil, fmt.Errorf("sfv: invalid path %q: invalid index %q", path, index)
+				}
+				step.index = n
+			}
+			i = end + 1
+		} else {
+			// a key
+			if !step.param && len(steps) > 0 {
+				return nil, fmt.Errorf("sfv: invalid path %q: a member key must be at the beginning", path)
+			}
+			end := i
+			for end < len(path) && path[end] != '[' && path[end] != ';' {
+				end++
+			}
+			step.key = path[i:end]
+			if !IsValidKey(step.key) {
+				return nil, fmt.Errorf("sfv: invalid path %q: invalid key %q", path, step.key)
+			}
+			i = end
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// match reports whether the node matches the step.
+// parent is the path of the parent node.
+func (step selectorStep) match(parent string, node Node) bool {
+	if step.key == "" {
+		return step.index < 0 || step.index == node.Index
+	}
+	if node.Key != step.key {
+		return false
+	}
+	// the key form selects only the node whose path has the key form.
+	if step.param {
+		return node.Path == parent+";"+step.key
+	}
+	return node.Path == parent+step.key
+}
+
+// memberNodes returns the members of the list, the dictionary, or the inner list.
+func memberNodes(node Node) []Node {
+	var nodes []Node
+	switch v := node.Value.(type) {
+	case List:
+		if node.Kind != NodeList {
+			break
+		}
+		for i, item := range v {
+			nodes = append(nodes, itemNode(Node{Path: indexPath(node.Path, i), Index: i}, item))
+		}
+	case Dictionary:
+		if node.Kind != NodeDictionary {
+			break
+		}
+		seenKeys := make(map[string]struct{}, len(v))
+		for i, member := range v {
+			_, dup := seenKeys[member.Key]
+			seenKeys[member.Key] = struct{}{}
+			nodes = append(nodes, itemNode(Node{
+				Path:  memberPath(node.Path, i, member.Key, !dup),
+				Key:   member.Key,
+				Index: i,
+			}, member.Item))
+		}
+	case InnerList:
+		if node.Kind != NodeInnerList {
+			break
+		}
+		for i, item := range v {
+			nodes = append(nodes, itemNode(Node{Path: indexPath(node.Path, i), Index: i}, item))
+		}
+	}
+	return nodes
+}
+
+// paramNodes returns the parameters of the item or the inner list.
+func paramNodes(node Node) []Node {
+	if node.Kind != NodeItem && node.Kind != NodeInnerList {
+		return nil
+	}
+	nodes := make([]Node, 0, len(node.Parameters))
+	for i, param := range node.Parameters {
+		unique := true
+		for _, prev := range node.Parameters[:i] {
+			if prev.Key == param.Key {
+				unique = false
+				break
+			}
+		}
+		nodes = append(nodes, Node{
+			Kind:  NodeParameter,
+			Path:  paramPath(node.Path, i, param.Key, unique),
+			Key:   param.Key,
+			Index: i,
+			Value: param.Value,
+		})
+	}
+	return nodes
+}
+
+// Select returns the nodes of v that match the path.
+// v must be an Item, a List or a Dictionary.
+//
+// The syntax of the path is same as ValidationError.Path and Node.Path,
+// and "[*]" matches any member, and ";[*]" matches any parameter.
+// For example, "a;b" selects the parameter "b" of the dictionary member "a",
+// and "[2][*]" selects all the items of the inner list that is the third member of the list.
+// The empty path selects v itself.
+//
+// It returns an error only if the path is malformed.
+// The nodes are in the same order as Walk.
+func Select(v interface{}, path string) ([]Node, error) {
+	steps, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var root Node
+	switch v := v.(type) {
+	case Item:
+		root = itemNode(Node{Index: -1}, v)
+	case List:
+		root = Node{Kind: NodeList, Index: -1, Value: v}
+	case Dictionary:
+		root = Node{Kind: NodeDictionary, Index: -1, Value: v}
+	default:
+		return nil, fmt.Errorf("sfv: unsupported type: %T", v)
+	}
+
+	nodes := []Node{root}
+	for _, step := range steps {
+		var next []Node
+		for _, node := range nodes {
+			var children []Node
+			if step.param {
+				children = paramNodes(node)
+			} else {
+				children = memberNodes(node)
+			}
+			for _, child := range children {
+				if step.match(node.Path, child) {
+					next = append(next, child)
+				}
+			}
+		}
+		nodes = next
+	}
+	return nodes, nil
+}
+
+// Encode serializes the node.
+// The parameters are serialized with the item and the inner list,
+// and only the value is serialized for the parameter.
+func (node Node) Encode() (string, error) {
+	state := getEncodeState()
+	defer putEncodeState(state)
+
+	var err error
+	switch node.Kind {
+	case NodeList:
+		list, _ := node.Value.(List)
+		err = state.encodeList(list)
+	case NodeDictionary:
+		dict, _ := node.Value.(Dictionary)
+		err = state.encodeDictionary(dict)
+	case NodeItem, NodeInnerList:
+		if err = state.encodeBareItemOrInnerList(node.Value); err == nil {
+			err = state.encodeParams(node.Parameters)
+		}
+	case NodeParameter:
+		err = state.encodeBareItem(node.Value)
+	default:
+		err = fmt.Errorf("sfv: unknown node kind: %s", node.Kind)
+	}
+	if err != nil {
+		return "", err
+	}
+	return state.buf.String(), nil
+}
@@ -0,0 +1,120 @@
+package sfv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelect(t *testing.T) {
+	dict := mustDecodeDictionary(t, `a=(1 "x";p=2);b=?0;c, d=tok;b=3, e`)
+
+	tests := []struct {
+		path  string
+		paths []string
+		enc   []string
+	}{
+		{"", []string{""}, []string{`a=(1 "x";p=2);b=?0;c, d=tok;b=3, e`}},
+		{"a", []string{"a"}, []string{`(1 "x";p=2);b=?0;c`}},
+		{"a;b", []string{"a;b"}, []string{`?0`}},
+		{"a[1]", []string{"a[1]"}, []string{`"x";p=2`}},
+		{"a[1];p", []string{"a[1];p"}, []string{`2`}},
+		{"a[*]", []string{"a[0]", "a[1]"}, []string{`1`, `"x";p=2`}},
+		{"a;[1]", []string{"a;c"}, []string{`?1`}},
+		{"[*];b", []string{"a;b", "d;b"}, []string{`?0`, `3`}},
+		{"[1]", []string{"d"}, []string{`tok;b=3`}},
+		{"[*][*];[*]", []string{"a[1];p"}, []string{`2`}},
+
+		// no match
+		{"missing", nil, nil},
+		{"a[2]", nil, nil},
+		{"d[0]", nil, nil},
+		{"a;b;c", nil, nil},
+		{"e;[0]", nil, nil},
+	}
+	for _, tt := range tests {
+		nodes, err := Select(dict, tt.path)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.path, err)
+			continue
+		}
+		var paths, enc []string
+		for _, node := range nodes {
+			paths = append(paths, node.Path)
+			s, err := node.Encode()
+			if err != nil {
+				t.Errorf("%q: unexpected error: %v", tt.path, err)
+				continue
+			}
+			enc = append(enc, s)
+		}
+		if !reflect.DeepEqual(paths, tt.paths) {
+			t.Errorf("%q: want %q, got %q", tt.path, tt.paths, paths)
+		}
+		if !reflect.DeepEqual(enc, tt.enc) {
+			t.Errorf("%q: want %q, got %q", tt.path, tt.enc, enc)
+		}
+	}
+}
+
+func TestSelect_list(t *testing.T) {
+	list := List{
+		{Value: int64(1)},
+		{Value: int64(2), Parameters: Parameters{{Key: "a", Value: int64(1)}, {Key: "a", Value: int64(2)}}},
+	}
+
+	nodes, err := Select(list, "[1];a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 1 || nodes[0].Value != int64(1) {
+		t.Errorf("unexpected nodes: %#v", nodes)
+	}
+
+	// the duplicated key is selected by index.
+	nodes, err = Select(list, "[1];[1]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 1 || nodes[0].Path != "[1];[1]" || nodes[0].Value != int64(2) {
+		t.Errorf("unexpected nodes: %#v", nodes)
+	}
+
+	// a key can't select a member of a list.
+	nodes, err = Select(list, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 0 {
+		t.Errorf("unexpected nodes: %#v", nodes)
+	}
+}
+
+func TestSelect_walkPaths(t *testing.T) {
+	// Select finds every node by the path reported by Walk.
+	dict := Dictionary{
+		{Key: "a", Item: Item{Value: InnerList{{Value: int64(1), Parameters: Parameters{{Key: "x", Value: true}}}}}},
+		{Key: "a", Item: Item{Value: int64(2)}},
+		{Key: "b", Item: Item{Value: true, Parameters: Parameters{{Key: "y", Value: true}, {Key: "y", Value: false}}}},
+	}
+	err := Walk(dict, func(node Node) error {
+		nodes, err := Select(dict, node.Path)
+		if err != nil {
+			return err
+		}
+		if len(nodes) != 1 || !reflect.DeepEqual(nodes[0], node) {
+			t.Errorf("%q: want %#v, got %#v", node.Path, node, nodes)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSelect_invalidPath(t *testing.T) {
+	for _, path := range []string{"A", "[", "[x]", "[-1]", "[+1]", "a[0]b", ";", "a;", "a;B"} {
+		if _, err := Select(List{}, path); err == nil {
+			t.Errorf("%q: want error, but not", path)
+		}
+	}
+}
@@ -52,9 +52,50 @@ func IsValidString(s string) bool {
 	return true
 }
 
+// IsValidKey returns whether the key has valid form.
+// The key must match the following regular expression:
+//
+//	[a-z*][a-z0-9_.*-]*
+func IsValidKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	if (key[0] < 'a' || key[0] > 'z') && key[0] != '*' {
+		return false
+	}
+	for _, ch := range []byte(key[1:]) {
+		if !validKeyChars[ch] {
+			return false
+		}
+	}
+	return true
+}
+
 // DisplayString is a unicode string.
 type DisplayString string
 
+// Raw is a pre-serialized bare item or inner list, without its parameters.
+// It is analogous to json.RawMessage; the encoder checks it with Valid, and writes it verbatim.
+// An inner list is allowed only as a member of Lists and Dictionaries,
+// and the encoder returns an error for Raw inner lists in the other places.
+type Raw string
+
+// Valid returns whether r is a valid serialization of a bare item or an inner list.
+func (r Raw) Valid() bool {
+	s := &decodeState{
+		fields: []string{string(r)},
+	}
+	if _, err := s.decodeBareItemOrInnerList(); err != nil {
+		return false
+	}
+	return s.peek() == endOfInput
+}
+
+// isInnerList reports whether r is an inner list.
+func (r Raw) isInnerList() bool {
+	return len(r) > 0 && r[0] == '('
+}
+
 // Value is a bare item.
 // It might be Integers, Decimals, Strings, Tokens, Byte Sequences, Booleans or Inner Lists.
 // It's type is one of these:
@@ -68,6 +109,7 @@ type DisplayString string
 //	time.Time for Date
 //	DisplayString for Display Strings
 //	InnerList for Inner Lists
+//	Raw for pre-serialized Bare Items or Inner Lists
 type Value interface{}
 
 // Parameter is a key-value pair of Parameters.
@@ -152,3 +152,32 @@ func TestIsValidString(t *testing.T) {
 		}
 	}
 }
+
+func TestRaw_Valid(t *testing.T) {
+	cases := []struct {
+		in   Raw
+		want bool
+	}{
+		{"", false},
+		{"1", true},
+		{"1.5", true},
+		{`"foo"`, true},
+		{"foo", true},
+		{":AQID:", true},
+		{"?1", true},
+		{"@1659578233", true},
+		{`%"f%c3%bc%c3%bc"`, true},
+		{"(1 2)", true},
+		{"()", true},
+		{"(1 (2))", false},
+		{"1;a=1", false},
+		{"1, 2", false},
+		{"(1 2", false},
+		{" 1", false},
+	}
+	for _, c := range cases {
+		if got := c.in.Valid(); got != c.want {
+			t.Errorf("Raw(%q).Valid() = %v, want %v", c.in, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,484 @@
+package sfv
+
+import (
+	"errors"
+	"strings"
+)
+
+// Pos is a position in the field lines.
+type Pos struct {
+	// Line is the index of the field line.
+	Line int
+
+	// Offset is the byte offset in the field line.
+	Offset int
+}
+
+// Span is a range in the field lines.
+// Start is inclusive and End is exclusive.
+type Span struct {
+	Start Pos
+	End   Pos
+}
+
+// KeySyntax is a key of a parameter or a dictionary member.
+type KeySyntax struct {
+	Span Span
+	Text string
+}
+
+// BareItemSyntax is a bare item.
+type BareItemSyntax struct {
+	Span Span
+
+	// Text is the original text of the bare item.
+	Text string
+
+	// Value is the decoded value.
+	Value Value
+}
+
+// ParameterSyntax is a parameter that starts with ';'.
+type ParameterSyntax struct {
+	Span Span
+
+	// Text is the original text of the parameter, including the leading ';'.
+	Text string
+
+	Key KeySyntax
+
+	// Value is nil if the value is omitted, that means true.
+	Value *BareItemSyntax
+}
+
+// InnerListSyntax is an inner list that starts with '(' and ends with ')'.
+type InnerListSyntax struct {
+	Span Span
+
+	// Text is the original text of the inner list, without its parameters.
+	Text string
+
+	Items []ItemSyntax
+}
+
+// ItemSyntax is an item or an inner list with its parameters.
+// Exactly one of BareItem and InnerList is non-nil,
+// except for dictionary members whose value is omitted.
+type ItemSyntax struct {
+	Span Span
+
+	// Text is the original text of the item, including its parameters.
+	Text string
+
+	BareItem   *BareItemSyntax
+	InnerList  *InnerListSyntax
+	Parameters []ParameterSyntax
+}
+
+// DictMemberSyntax is a member of a dictionary.
+type DictMemberSyntax struct {
+	Span Span
+
+	// Text is the original text of the member, including its key and parameters.
+	Text string
+
+	Key  KeySyntax
+	Item ItemSyntax
+}
+
+// ListSyntax is a list with the field lines it decoded from.
+type ListSyntax struct {
+	Fields  []string
+	Members []ItemSyntax
+}
+
+// DictionarySyntax is a dictionary with the field lines it decoded from.
+// Unlike Dictionary, it keeps duplicated keys in their original order.
+type DictionarySyntax struct {
+	Fields  []string
+	Members []DictMemberSyntax
+}
+
+// Item returns the item that the syntax represents.
+func (item *ItemSyntax) Item() Item {
+	var ret Item
+	switch {
+	case item.BareItem != nil:
+		ret.Value = item.BareItem.Value
+	case item.InnerList != nil:
+		list := make(InnerList, 0, len(item.InnerList.Items))
+		for i := range item.InnerList.Items {
+			list = append(list, item.InnerList.Items[i].Item())
+		}
+		ret.Value = list
+	default:
+		ret.Value = true
+	}
+
+	// overwrite duplicated parameters in the same way as decodeParameters.
+	for _, param := range item.Parameters {
+		var value Value = true
+		if param.Value != nil {
+			value = param.Value.Value
+		}
+		found := false
+		for i := range ret.Parameters {
+			if ret.Parameters[i].Key == param.Key.Text {
+				ret.Parameters[i].Value = value
+				found = true
+				break
+			}
+		}
+		if !found {
+			ret.Parameters = append(ret.Parameters, Parameter{
+				Key:   param.Key.Text,
+				Value: value,
+			})
+		}
+	}
+	return ret
+}
+
+// List returns the list that the syntax represents.
+func (list *ListSyntax) List() List {
+	var ret List
+	for i := range list.Members {
+		ret = append(ret, list.Members[i].Item())
+	}
+	return ret
+}
+
+// Dictionary returns the dictionary that the syntax represents.
+// The value of duplicated keys are overwritten in the same way as DecodeDictionary.
+func (dict *DictionarySyntax) Dictionary() Dictionary {
+	var ret Dictionary
+	seenKeys := map[string]int{}
+	for i := range dict.Members {
+		member := &dict.Members[i]
+		item := member.Item.Item()
+		if j, ok := seenKeys[member.Key.Text]; ok {
+			ret[j].Item = item
+		} else {
+			seenKeys[member.Key.Text] = len(ret)
+			ret = append(ret, DictMember{
+				Key:  member.Key.Text,
+				Item: item,
+			})
+		}
+	}
+	return ret
+}
+
+// pos returns the position of the next character.
+func (s *decodeState) pos() Pos {
+	if s.endOfField {
+		// the current character is the separator between field lines.
+		// it is treated as the end of the previous line.
+		return Pos{Line: s.line - 1, Offset: len(s.fields[s.line-1])}
+	}
+	return Pos{Line: s.line, Offset: s.col}
+}
+
+// span returns the span from start to the current position.
+func (s *decodeState) span(start Pos) Span {
+	return Span{Start: start, End: s.pos()}
+}
+
+// text returns the original text in the span.
+// The field lines are joined with ", " if the span crosses them.
+func (s *decodeState) text(span Span) string {
+	start, end := span.Start, span.End
+	if start.Line == end.Line {
+		return s.fields[start.Line][start.Offset:end.Offset]
+	}
+	var buf strings.Builder
+	buf.WriteString(s.fields[start.Line][start.Offset:])
+	for i := start.Line + 1; i < end.Line; i++ {
+		buf.WriteString(", ")
+		buf.WriteString(s.fields[i])
+	}
+	buf.WriteString(", ")
+	buf.WriteString(s.fields[end.Line][:end.Offset])
+	return buf.String()
+}
+
+func (s *decodeState) decodeKeySyntax() (KeySyntax, error) {
+	start := s.pos()
+	key, err := s.decodeKey()
+	if err != nil {
+		return KeySyntax{}, err
+	}
+	return KeySyntax{
+		Span: s.span(start),
+		Text: key,
+	}, nil
+}
+
+func (s *decodeState) decodeBareItemSyntax() (*BareItemSyntax, error) {
+	start := s.pos()
+	v, err := s.decodeBareItem()
+	if err != nil {
+		return nil, err
+	}
+	span := s.span(start)
+	return &BareItemSyntax{
+		Span:  span,
+		Text:  s.text(span),
+		Value: v,
+	}, nil
+}
+
+func (s *decodeState) decodeParametersSyntax() ([]ParameterSyntax, error) {
+	var params []ParameterSyntax
+	for {
+		if s.peek() != ';' {
+			break
+		}
+		start := s.pos()
+		s.next() // skip ';'
+		s.skipSPs()
+
+		key, err := s.decodeKeySyntax()
+		if err != nil {
+			return nil, err
+		}
+		var value *BareItemSyntax
+		if s.peek() == '=' {
+			s.next() // skip '='
+			value, err = s.decodeBareItemSyntax()
+			if err != nil {
+				return nil, err
+			}
+		}
+		span := s.span(start)
+		params = append(params, ParameterSyntax{
+			Span:  span,
+			Text:  s.text(span),
+			Key:   key,
+			Value: value,
+		})
+	}
+	return params, nil
+}
+
+func (s *decodeState) decodeItemSyntax() (ItemSyntax, error) {
+	start := s.pos()
+	v, err := s.decodeBareItemSyntax()
+	if err != nil {
+		return ItemSyntax{}, err
+	}
+	params, err := s.decodeParametersSyntax()
+	if err != nil {
+		return ItemSyntax{}, err
+	}
+	span := s.span(start)
+	return ItemSyntax{
+		Span:       span,
+		Text:       s.text(span),
+		BareItem:   v,
+		Parameters: params,
+	}, nil
+}
+
+func (s *decodeState) decodeItemOrInnerListSyntax() (ItemSyntax, error) {
+	if s.peek() != '(' {
+		// It might be an Item
+		return s.decodeItemSyntax()
+	}
+	start := s.pos()
+	s.next() // skip '('
+
+	// parse as an Inner List
+	list := &InnerListSyntax{
+		Items: []ItemSyntax{},
+	}
+	for {
+		s.skipSPs()
+		ch := s.peek()
+		if ch == ')' {
+			s.next() // skip ')'
+			break
+		}
+
+		item, err := s.decodeItemSyntax()
+		if err != nil {
+			return ItemSyntax{}, err
+		}
+		list.Items = append(list.Items, item)
+		ch = s.peek()
+		if ch != ' ' && ch != ')' {
+			return ItemSyntax{}, s.errUnexpectedCharacter()
+		}
+	}
+	list.Span = s.span(start)
+	list.Text = s.text(list.Span)
+
+	params, err := s.decodeParametersSyntax()
+	if err != nil {
+		return ItemSyntax{}, err
+	}
+	span := s.span(start)
+	return ItemSyntax{
+		Span:       span,
+		Text:       s.text(span),
+		InnerList:  list,
+		Parameters: params,
+	}, nil
+}
+
+func (s *decodeState) decodeListSyntax() ([]ItemSyntax, error) {
+	var list []ItemSyntax
+
+	if s.peek() == endOfInput {
+		// it is an empty list
+		return nil, nil
+	}
+
+	for {
+		item, err := s.decodeItemOrInnerListSyntax()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, item)
+
+		s.skipOWS()
+		ch := s.peek()
+		if ch == endOfInput {
+			break
+		}
+		if ch != ',' {
+			return nil, s.errUnexpectedCharacter()
+		}
+		s.next() // skip ','
+		s.skipOWS()
+		if s.peek() == endOfInput {
+			// it is trailing comma.
+			return nil, errors.New("sfv: trailing comma is not allowed")
+		}
+	}
+	return list, nil
+}
+
+func (s *decodeState) decodeDictionarySyntax() ([]DictMemberSyntax, error) {
+	if s.peek() == endOfInput {
+		// it is an empty dictionary
+		return nil, nil
+	}
+
+	var dict []DictMemberSyntax
+	for {
+		start := s.pos()
+
+		// decode keys
+		key, err := s.decodeKeySyntax()
+		if err != nil {
+			return nil, err
+		}
+
+		// decode items
+		var item ItemSyntax
+		if s.peek() == '=' {
+			s.next() // skip '='
+			item, err = s.decodeItemOrInnerListSyntax()
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			itemStart := s.pos()
+			params, err := s.decodeParametersSyntax()
+			if err != nil {
+				return nil, err
+			}
+			span := s.span(itemStart)
+			item = ItemSyntax{
+				Span:       span,
+				Text:       s.text(span),
+				Parameters: params,
+			}
+		}
+		span := s.span(start)
+		dict = append(dict, DictMemberSyntax{
+			Span: span,
+			Text: s.text(span),
+			Key:  key,
+			Item: item,
+		})
+
+		// skip commas
+		s.skipOWS()
+		ch := s.peek()
+		if ch == endOfInput {
+			break
+		}
+		if ch != ',' {
+			return nil, s.errUnexpectedCharacter()
+		}
+		s.next() // skip ','
+		s.skipOWS()
+		if s.peek() == endOfInput {
+			// it is trailing comma.
+			return nil, errors.New("sfv: trailing comma is not allowed")
+		}
+	}
+	return dict, nil
+}
+
+// DecodeItemSyntax decodes fields as Structured Field Values,
+// and returns the concrete syntax tree of an Item.
+func DecodeItemSyntax(fields []string) (*ItemSyntax, error) {
+	state := &decodeState{
+		fields: fields,
+	}
+	state.skipSPs()
+	ret, err := state.decodeItemSyntax()
+	if err != nil {
+		return nil, err
+	}
+	state.skipSPs()
+	if state.peek() != endOfInput {
+		return nil, state.errUnexpectedCharacter()
+	}
+	return &ret, nil
+}
+
+// DecodeListSyntax decodes fields as Structured Field Values,
+// and returns the concrete syntax tree of a List.
+func DecodeListSyntax(fields []string) (*ListSyntax, error) {
+	state := &decodeState{
+		fields: fields,
+	}
+	state.skipSPs()
+	ret, err := state.decodeListSyntax()
+	if err != nil {
+		return nil, err
+	}
+	state.skipSPs()
+	if state.peek() != endOfInput {
+		return nil, state.errUnexpectedCharacter()
+	}
+	return &ListSyntax{
+		Fields:  fields,
+		Members: ret,
+	}, nil
+}
+
+// DecodeDictionarySyntax decodes fields as Structured Field Values,
+// and returns the concrete syntax tree of a Dictionary.
+func DecodeDictionarySyntax(fields []string) (*DictionarySyntax, error) {
+	state := &decodeState{
+		fields: fields,
+	}
+	state.skipSPs()
+	ret, err := state.decodeDictionarySyntax()
+	if err != nil {
+		return nil, err
+	}
+	state.skipSPs()
+	if state.peek() != endOfInput {
+		return nil, state.errUnexpectedCharacter()
+	}
+	return &DictionarySyntax{
+		Fields:  fields,
+		Members: ret,
+	}, nil
+}
@@ -0,0 +1,151 @@
+package sfv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeSyntax(t *testing.T) {
+	for _, tt := range allTestCases(t) {
+		tt := tt
+		t.Run(tt.Name, func(t *testing.T) {
+			switch tt.HeaderType {
+			case headerTypeItem:
+				want, wantErr := DecodeItem(tt.Raw)
+				got, err := DecodeItemSyntax(tt.Raw)
+				if (err != nil) != (wantErr != nil) {
+					t.Fatalf("want error %v, got %v", wantErr, err)
+				}
+				if err != nil {
+					return
+				}
+				checkSpan(t, tt.Raw, got.Span, got.Text)
+				if item := got.Item(); !reflect.DeepEqual(item, want) {
+					t.Errorf("want %#v, got %#v", want, item)
+				}
+			case headerTypeList:
+				want, wantErr := DecodeList(tt.Raw)
+				got, err := DecodeListSyntax(tt.Raw)
+				if (err != nil) != (wantErr != nil) {
+					t.Fatalf("want error %v, got %v", wantErr, err)
+				}
+				if err != nil {
+					return
+				}
+				for _, member := range got.Members {
+					checkSpan(t, tt.Raw, member.Span, member.Text)
+				}
+				if list := got.List(); !reflect.DeepEqual(list, want) {
+					t.Errorf("want %#v, got %#v", want, list)
+				}
+			case headerTypeDictionary:
+				want, wantErr := DecodeDictionary(tt.Raw)
+				got, err := DecodeDictionarySyntax(tt.Raw)
+				if (err != nil) != (wantErr != nil) {
+					t.Fatalf("want error %v, got %v", wantErr, err)
+				}
+				if err != nil {
+					return
+				}
+				for _, member := range got.Members {
+					checkSpan(t, tt.Raw, member.Span, member.Text)
+				}
+				if dict := got.Dictionary(); !reflect.DeepEqual(dict, want) {
+					t.Errorf("want %#v, got %#v", want, dict)
+				}
+			}
+		})
+	}
+}
+
+func checkSpan(t *testing.T, fields []string, span Span, text string) {
+	t.Helper()
+	s := &decodeState{fields: fields}
+	if got := s.text(span); got != text {
+		t.Errorf("the text of %v: want %q, got %q", span, got, text)
+	}
+}
+
+func TestDecodeDictionarySyntax(t *testing.T) {
+	fields := []string{
+		`a=1.50;x,  b=("foo"   bar)`,
+		`a=?0`,
+	}
+	dict, err := DecodeDictionarySyntax(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dict.Members) != 3 {
+		t.Fatalf("want 3 members, got %d", len(dict.Members))
+	}
+
+	// the first member
+	a := dict.Members[0]
+	if a.Text != "a=1.50;x" {
+		t.Errorf("want %q, got %q", "a=1.50;x", a.Text)
+	}
+	if want := (Span{Start: Pos{0, 0}, End: Pos{0, 8}}); a.Span != want {
+		t.Errorf("want %v, got %v", want, a.Span)
+	}
+	if want := (Span{Start: Pos{0, 0}, End: Pos{0, 1}}); a.Key.Span != want {
+		t.Errorf("want %v, got %v", want, a.Key.Span)
+	}
+	if a.Item.BareItem.Text != "1.50" || a.Item.BareItem.Value != 1.5 {
+		t.Errorf("unexpected bare item: %#v", a.Item.BareItem)
+	}
+	if len(a.Item.Parameters) != 1 || a.Item.Parameters[0].Text != ";x" || a.Item.Parameters[0].Value != nil {
+		t.Errorf("unexpected parameters: %#v", a.Item.Parameters)
+	}
+
+	// the second member
+	b := dict.Members[1]
+	if b.Text != `b=("foo"   bar)` {
+		t.Errorf("want %q, got %q", `b=("foo"   bar)`, b.Text)
+	}
+	if want := (Span{Start: Pos{0, 11}, End: Pos{0, 26}}); b.Span != want {
+		t.Errorf("want %v, got %v", want, b.Span)
+	}
+	items := b.Item.InnerList.Items
+	if len(items) != 2 {
+		t.Fatalf("want 2 items, got %d", len(items))
+	}
+	if want := (Span{Start: Pos{0, 22}, End: Pos{0, 25}}); items[1].Span != want {
+		t.Errorf("want %v, got %v", want, items[1].Span)
+	}
+
+	// the duplicated member in the second line
+	a2 := dict.Members[2]
+	if a2.Text != "a=?0" {
+		t.Errorf("want %q, got %q", "a=?0", a2.Text)
+	}
+	if want := (Span{Start: Pos{1, 0}, End: Pos{1, 4}}); a2.Span != want {
+		t.Errorf("want %v, got %v", want, a2.Span)
+	}
+
+	// the last one wins
+	if got := dict.Dictionary().Get("a").Value; got != false {
+		t.Errorf("want false, got %v", got)
+	}
+}
+
+func TestDecodeListSyntax_crossingLines(t *testing.T) {
+	// a string may contain the separator of field lines.
+	fields := []string{`"foo`, `bar";x=1`}
+	list, err := DecodeListSyntax(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Members) != 1 {
+		t.Fatalf("want 1 member, got %d", len(list.Members))
+	}
+	member := list.Members[0]
+	if want := (Span{Start: Pos{0, 0}, End: Pos{1, 8}}); member.Span != want {
+		t.Errorf("want %v, got %v", want, member.Span)
+	}
+	if want := `"foo, bar";x=1`; member.Text != want {
+		t.Errorf("want %q, got %q", want, member.Text)
+	}
+	if want := (Span{Start: Pos{0, 0}, End: Pos{1, 4}}); member.BareItem.Span != want {
+		t.Errorf("want %v, got %v", want, member.BareItem.Span)
+	}
+}
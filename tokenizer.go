@@ -0,0 +1,332 @@
+package sfv
+
+import (
+	"errors"
+	"io"
+	"strconv"
+)
+
+// EventKind is the kind of an Event.
+type EventKind int
+
+const (
+	// EventBareItem is the bare item of an item, or an item in an inner list.
+	EventBareItem EventKind = iota + 1
+
+	// EventStartInnerList is the '(' that starts an inner list.
+	EventStartInnerList
+
+	// EventEndInnerList is the ')' that ends an inner list.
+	EventEndInnerList
+
+	// EventParamKey is the key of a parameter.
+	// The value of the parameter follows as EventParamValue.
+	// If the value is omitted, it is true and no EventParamValue follows.
+	EventParamKey
+
+	// EventParamValue is the bare item of a parameter.
+	EventParamValue
+
+	// EventDictKey is the key of a dictionary member.
+	// The value of the member follows as EventBareItem or EventStartInnerList.
+	// If the value is omitted, it is true and only its parameters follow.
+	EventDictKey
+
+	// EventMemberSeparator is the ',' between the members of a list or a dictionary.
+	EventMemberSeparator
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventBareItem:
+		return "BareItem"
+	case EventStartInnerList:
+		return "StartInnerList"
+	case EventEndInnerList:
+		return "EndInnerList"
+	case EventParamKey:
+		return "ParamKey"
+	case EventParamValue:
+		return "ParamValue"
+	case EventDictKey:
+		return "DictKey"
+	case EventMemberSeparator:
+		return "MemberSeparator"
+	}
+	return "EventKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// BareItemKind is the type of a bare item.
+type BareItemKind int
+
+const (
+	BareItemInteger BareItemKind = iota + 1
+	BareItemDecimal
+	BareItemString
+	BareItemToken
+	BareItemByteSequence
+	BareItemBoolean
+	BareItemDate
+	BareItemDisplayString
+)
+
+func (k BareItemKind) String() string {
+	switch k {
+	case BareItemInteger:
+		return "Integer"
+	case BareItemDecimal:
+		return "Decimal"
+	case BareItemString:
+		return "String"
+	case BareItemToken:
+		return "Token"
+	case BareItemByteSequence:
+		return "ByteSequence"
+	case BareItemBoolean:
+		return "Boolean"
+	case BareItemDate:
+		return "Date"
+	case BareItemDisplayString:
+		return "DisplayString"
+	}
+	return "BareItemKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Event is a token of a structured field value.
+type Event struct {
+	Kind EventKind
+
+	// ItemKind is the type of the bare item.
+	// It is set only for EventBareItem and EventParamValue.
+	ItemKind BareItemKind
+
+	// Raw is the original text of the token.
+	// It is the serialized bare item for EventBareItem and EventParamValue,
+	// and the key for EventParamKey and EventDictKey.
+	// It is empty for the EventMemberSeparator between field lines.
+	Raw string
+
+	Span Span
+}
+
+// fieldType is the top-level type of a structured field.
+type fieldType int
+
+const (
+	fieldTypeItem fieldType = iota
+	fieldTypeList
+	fieldTypeDictionary
+)
+
+type tokenizerState int
+
+const (
+	tokenizerMemberStart tokenizerState = iota
+	tokenizerDictValue
+	tokenizerValue
+	tokenizerInnerList
+	tokenizerParams
+	tokenizerParamValue
+	tokenizerMemberEnd
+	tokenizerEOF
+	tokenizerError
+)
+
+// Tokenizer reads the tokens of a structured field value one by one.
+// It validates the syntax in the same way as DecodeItem, DecodeList and DecodeDictionary,
+// but doesn't build the values, nor detect duplicated keys.
+type Tokenizer struct {
+	s       decodeState
+	top     fieldType
+	state   tokenizerState
+	inInner bool
+	err     error
+}
+
+// NewItemTokenizer returns a new Tokenizer that reads fields as an Item.
+func NewItemTokenizer(fields []string) *Tokenizer {
+	return newTokenizer(fields, fieldTypeItem)
+}
+
+// NewListTokenizer returns a new Tokenizer that reads fields as a List.
+func NewListTokenizer(fields []string) *Tokenizer {
+	return newTokenizer(fields, fieldTypeList)
+}
+
+// NewDictionaryTokenizer returns a new Tokenizer that reads fields as a Dictionary.
+func NewDictionaryTokenizer(fields []string) *Tokenizer {
+	return newTokenizer(fields, fieldTypeDictionary)
+}
+
+func newTokenizer(fields []string, top fieldType) *Tokenizer {
+	t := &Tokenizer{
+		s: decodeState{
+			fields: fields,
+		},
+		top: top,
+	}
+	t.s.skipSPs()
+	if top != fieldTypeItem && t.s.peek() == endOfInput {
+		// it is an empty list or dictionary.
+		t.state = tokenizerEOF
+	}
+	return t
+}
+
+// Next returns the next token.
+// It returns io.EOF at the end of the input.
+// Once it returns an error, it returns the same error.
+func (t *Tokenizer) Next() (Event, error) {
+	ev, ok, err := t.next()
+	for !ok && err == nil {
+		ev, ok, err = t.next()
+	}
+	if err != nil {
+		if t.state != tokenizerEOF {
+			t.state = tokenizerError
+			t.err = err
+		}
+		return Event{}, err
+	}
+	return ev, nil
+}
+
+// next advances the state machine by one step.
+// ok is false if the step emits no event.
+func (t *Tokenizer) next() (ev Event, ok bool, err error) {
+	s := &t.s
+	switch t.state {
+	case tokenizerMemberStart:
+		if t.top != fieldTypeDictionary {
+			t.state = tokenizerValue
+			return Event{}, false, nil
+		}
+		start := s.pos()
+		if err := s.skipKey(); err != nil {
+			return Event{}, false, err
+		}
+		t.state = tokenizerDictValue
+		return t.event(EventDictKey, 0, start), true, nil
+
+	case tokenizerDictValue:
+		if s.peek() == '=' {
+			s.next() // skip '='
+			t.state = tokenizerValue
+		} else {
+			t.state = tokenizerParams
+		}
+		return Event{}, false, nil
+
+	case tokenizerValue:
+		start := s.pos()
+		if s.peek() == '(' && t.top != fieldTypeItem {
+			s.next() // skip '('
+			t.state = tokenizerInnerList
+			t.inInner = true
+			return t.event(EventStartInnerList, 0, start), true, nil
+		}
+		kind, err := s.skipBareItem()
+		if err != nil {
+			return Event{}, false, err
+		}
+		t.state = tokenizerParams
+		return t.event(EventBareItem, kind, start), true, nil
+
+	case tokenizerInnerList:
+		s.skipSPs()
+		start := s.pos()
+		if s.peek() == ')' {
+			s.next() // skip ')'
+			t.state = tokenizerParams
+			t.inInner = false
+			return t.event(EventEndInnerList, 0, start), true, nil
+		}
+		kind, err := s.skipBareItem()
+		if err != nil {
+			return Event{}, false, err
+		}
+		t.state = tokenizerParams
+		return t.event(EventBareItem, kind, start), true, nil
+
+	case tokenizerParams:
+		if s.peek() != ';' {
+			if t.inInner {
+				// the end of an item in the inner list.
+				if ch := s.peek(); ch != ' ' && ch != ')' {
+					return Event{}, false, s.errUnexpectedCharacter()
+				}
+				t.state = tokenizerInnerList
+			} else {
+				t.state = tokenizerMemberEnd
+			}
+			return Event{}, false, nil
+		}
+		s.next() // skip ';'
+		s.skipSPs()
+		start := s.pos()
+		if err := s.skipKey(); err != nil {
+			return Event{}, false, err
+		}
+		t.state = tokenizerParamValue
+		return t.event(EventParamKey, 0, start), true, nil
+
+	case tokenizerParamValue:
+		t.state = tokenizerParams
+		if s.peek() != '=' {
+			// the value is omitted.
+			return Event{}, false, nil
+		}
+		s.next() // skip '='
+		start := s.pos()
+		kind, err := s.skipBareItem()
+		if err != nil {
+			return Event{}, false, err
+		}
+		return t.event(EventParamValue, kind, start), true, nil
+
+	case tokenizerMemberEnd:
+		if t.top == fieldTypeItem {
+			s.skipSPs()
+			if s.peek() != endOfInput {
+				return Event{}, false, s.errUnexpectedCharacter()
+			}
+			t.state = tokenizerEOF
+			return Event{}, false, io.EOF
+		}
+
+		s.skipOWS()
+		ch := s.peek()
+		if ch == endOfInput {
+			t.state = tokenizerEOF
+			return Event{}, false, io.EOF
+		}
+		if ch != ',' {
+			return Event{}, false, s.errUnexpectedCharacter()
+		}
+		start := s.pos()
+		s.next() // skip ','
+		ev := t.event(EventMemberSeparator, 0, start)
+		s.skipOWS()
+		if s.peek() == endOfInput {
+			// it is trailing comma.
+			return Event{}, false, errors.New("sfv: trailing comma is not allowed")
+		}
+		t.state = tokenizerMemberStart
+		return ev, true, nil
+
+	case tokenizerEOF:
+		return Event{}, false, io.EOF
+	}
+	return Event{}, false, t.err
+}
+
+// event returns the event from start to the current position.
+func (t *Tokenizer) event(kind EventKind, itemKind BareItemKind, start Pos) Event {
+	span := t.s.span(start)
+	return Event{
+		Kind:     kind,
+		ItemKind: itemKind,
+		Raw:      t.s.text(span),
+		Span:     span,
+	}
+}
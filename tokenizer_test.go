@@ -0,0 +1,247 @@
+package sfv
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestTokenizer(t *testing.T) {
+	for _, tt := range allTestCases(t) {
+		tt := tt
+		t.Run(tt.Name, func(t *testing.T) {
+			var tokenizer *Tokenizer
+			var want interface{}
+			var wantErr error
+			switch tt.HeaderType {
+			case headerTypeItem:
+				tokenizer = NewItemTokenizer(tt.Raw)
+				want, wantErr = DecodeItem(tt.Raw)
+			case headerTypeList:
+				tokenizer = NewListTokenizer(tt.Raw)
+				want, wantErr = DecodeList(tt.Raw)
+			case headerTypeDictionary:
+				tokenizer = NewDictionaryTokenizer(tt.Raw)
+				want, wantErr = DecodeDictionary(tt.Raw)
+			}
+
+			var events []Event
+			var err error
+			for {
+				var ev Event
+				ev, err = tokenizer.Next()
+				if err != nil {
+					break
+				}
+				checkSpan(t, tt.Raw, ev.Span, ev.Raw)
+				events = append(events, ev)
+			}
+			if err == io.EOF {
+				err = nil
+			}
+			if (err != nil) != (wantErr != nil) {
+				t.Fatalf("want error %v, got %v", wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			b := &eventBuilder{t: t, events: events}
+			var got interface{}
+			switch tt.HeaderType {
+			case headerTypeItem:
+				got = b.item()
+			case headerTypeList:
+				got = b.list()
+			case headerTypeDictionary:
+				got = b.dictionary()
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("want %#v, got %#v", want, got)
+			}
+		})
+	}
+}
+
+func TestTokenizer_events(t *testing.T) {
+	tokenizer := NewDictionaryTokenizer([]string{`a=(1 "x";y);z, b`, `c;d=?0`})
+	want := []Event{
+		{Kind: EventDictKey, Raw: "a", Span: Span{Pos{0, 0}, Pos{0, 1}}},
+		{Kind: EventStartInnerList, Raw: "(", Span: Span{Pos{0, 2}, Pos{0, 3}}},
+		{Kind: EventBareItem, ItemKind: BareItemInteger, Raw: "1", Span: Span{Pos{0, 3}, Pos{0, 4}}},
+		{Kind: EventBareItem, ItemKind: BareItemString, Raw: `"x"`, Span: Span{Pos{0, 5}, Pos{0, 8}}},
+		{Kind: EventParamKey, Raw: "y", Span: Span{Pos{0, 9}, Pos{0, 10}}},
+		{Kind: EventEndInnerList, Raw: ")", Span: Span{Pos{0, 10}, Pos{0, 11}}},
+		{Kind: EventParamKey, Raw: "z", Span: Span{Pos{0, 12}, Pos{0, 13}}},
+		{Kind: EventMemberSeparator, Raw: ",", Span: Span{Pos{0, 13}, Pos{0, 14}}},
+		{Kind: EventDictKey, Raw: "b", Span: Span{Pos{0, 15}, Pos{0, 16}}},
+		{Kind: EventMemberSeparator, Raw: "", Span: Span{Pos{0, 16}, Pos{0, 16}}},
+		{Kind: EventDictKey, Raw: "c", Span: Span{Pos{1, 0}, Pos{1, 1}}},
+		{Kind: EventParamKey, Raw: "d", Span: Span{Pos{1, 2}, Pos{1, 3}}},
+		{Kind: EventParamValue, ItemKind: BareItemBoolean, Raw: "?0", Span: Span{Pos{1, 4}, Pos{1, 6}}},
+	}
+	for i, w := range want {
+		got, err := tokenizer.Next()
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+		if got != w {
+			t.Errorf("%d: want %#v, got %#v", i, w, got)
+		}
+	}
+	if _, err := tokenizer.Next(); err != io.EOF {
+		t.Errorf("want io.EOF, got %v", err)
+	}
+	if _, err := tokenizer.Next(); err != io.EOF {
+		t.Errorf("want io.EOF, got %v", err)
+	}
+}
+
+func TestTokenizer_error(t *testing.T) {
+	tokenizer := NewListTokenizer([]string{"a, b,"})
+	var err error
+	for err == nil {
+		_, err = tokenizer.Next()
+	}
+	if err == io.EOF {
+		t.Fatal("want error, got io.EOF")
+	}
+	if _, err2 := tokenizer.Next(); err2 != err {
+		t.Errorf("want %v, got %v", err, err2)
+	}
+}
+
+func BenchmarkTokenizer(b *testing.B) {
+	fields := []string{`a=("foo" "bar");x=1;y=2.5, b=:AQID:, c=%"caf%c3%a9", d=@1659578233`}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tokenizer := NewDictionaryTokenizer(fields)
+		for {
+			_, err := tokenizer.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// eventBuilder rebuilds the value from the events.
+type eventBuilder struct {
+	t      *testing.T
+	events []Event
+	pos    int
+}
+
+func (b *eventBuilder) peek() EventKind {
+	if b.pos >= len(b.events) {
+		return 0
+	}
+	return b.events[b.pos].Kind
+}
+
+func (b *eventBuilder) next(kind EventKind) Event {
+	b.t.Helper()
+	if b.peek() != kind {
+		b.t.Fatalf("event %d: want %s, got %s", b.pos, kind, b.peek())
+	}
+	ev := b.events[b.pos]
+	b.pos++
+	return ev
+}
+
+func (b *eventBuilder) bareItem(ev Event) Value {
+	b.t.Helper()
+	s := &decodeState{fields: []string{ev.Raw}}
+	v, err := s.decodeBareItem()
+	if err != nil {
+		b.t.Fatal(err)
+	}
+	return v
+}
+
+func (b *eventBuilder) params() Parameters {
+	var params Parameters
+	for b.peek() == EventParamKey {
+		param := Parameter{
+			Key:   b.next(EventParamKey).Raw,
+			Value: true,
+		}
+		if b.peek() == EventParamValue {
+			param.Value = b.bareItem(b.next(EventParamValue))
+		}
+		replaced := false
+		for i := range params {
+			if params[i].Key == param.Key {
+				params[i] = param
+				replaced = true
+			}
+		}
+		if !replaced {
+			params = append(params, param)
+		}
+	}
+	return params
+}
+
+func (b *eventBuilder) item() Item {
+	value := b.bareItem(b.next(EventBareItem))
+	return Item{Value: value, Parameters: b.params()}
+}
+
+func (b *eventBuilder) itemOrInnerList() Item {
+	if b.peek() != EventStartInnerList {
+		return b.item()
+	}
+	b.next(EventStartInnerList)
+	list := InnerList{}
+	for b.peek() != EventEndInnerList {
+		list = append(list, b.item())
+	}
+	b.next(EventEndInnerList)
+	return Item{Value: list, Parameters: b.params()}
+}
+
+func (b *eventBuilder) list() List {
+	var list List
+	for b.pos < len(b.events) {
+		if len(list) > 0 {
+			b.next(EventMemberSeparator)
+		}
+		list = append(list, b.itemOrInnerList())
+	}
+	return list
+}
+
+func (b *eventBuilder) dictionary() Dictionary {
+	var dict Dictionary
+	first := true
+	for b.pos < len(b.events) {
+		if !first {
+			b.next(EventMemberSeparator)
+		}
+		first = false
+
+		member := DictMember{Key: b.next(EventDictKey).Raw}
+		if k := b.peek(); k == EventBareItem || k == EventStartInnerList {
+			member.Item = b.itemOrInnerList()
+		} else {
+			member.Item = Item{Value: true, Parameters: b.params()}
+		}
+		replaced := false
+		for i := range dict {
+			if dict[i].Key == member.Key {
+				dict[i] = member
+				replaced = true
+			}
+		}
+		if !replaced {
+			dict = append(dict, member)
+		}
+	}
+	return dict
+}
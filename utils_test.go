@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -404,3 +405,26 @@ func checkDictionary(t *testContext, got Dictionary, want interface{}) {
 		checkItem(t.Index(i), got.Item, kv[1])
 	}
 }
+
+// allTestCases returns all the test cases for parsing.
+func allTestCases(t *testing.T) []*testCase {
+	t.Helper()
+	files, err := filepath.Glob("./testdata/structured-field-tests/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files = append(files, "./testdata/extra.json")
+
+	var ret []*testCase
+	for _, filename := range files {
+		cases, err := readTestCases(filename)
+		if err != nil {
+			t.Fatalf("failed to read %q: %v", filename, err)
+		}
+		for _, tt := range cases {
+			tt.Name = filepath.Base(filename) + "/" + tt.Name
+		}
+		ret = append(ret, cases...)
+	}
+	return ret
+}
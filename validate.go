@@ -0,0 +1,256 @@
+package sfv
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidationError is a problem found by ValidateItem, ValidateList and ValidateDictionary.
+type ValidationError struct {
+	// Path is the location of the problem.
+	// It is empty for the top-level item, and consists of the following elements:
+	//
+	//	key   the member of the dictionary with the key
+	//	[i]   the i-th member of the list or the dictionary, or the i-th item of the inner list
+	//	;key  the parameter with the key
+	//	;[i]  the i-th parameter
+	//
+	// The index form is used for the members and the parameters with invalid or duplicated keys.
+	// For example, "a[1];b" means the parameter "b" of the second item of the inner list
+	// that is the member "a" of the dictionary.
+	Path string
+
+	// Reason describes the problem.
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return "sfv: " + e.Reason
+	}
+	return "sfv: " + e.Path + ": " + e.Reason
+}
+
+// ValidationErrors is a list of problems found by ValidateItem, ValidateList and ValidateDictionary.
+// Each element is a *ValidationError.
+type ValidationErrors []error
+
+func (errs ValidationErrors) Error() string {
+	var buf strings.Builder
+	for i, err := range errs {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(err.Error())
+	}
+	return buf.String()
+}
+
+// indexPath returns the path to the i-th element.
+func indexPath(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+// memberPath returns the path to the member of a dictionary.
+func memberPath(path string, i int, key string, unique bool) string {
+	if unique && IsValidKey(key) {
+		return path + key
+	}
+	return indexPath(path, i)
+}
+
+// paramPath returns the path to the parameter.
+func paramPath(path string, i int, key string, unique bool) string {
+	if unique && IsValidKey(key) {
+		return path + ";" + key
+	}
+	return indexPath(path+";", i)
+}
+
+// validator checks the whole tree of a structured field value,
+// and collects all the problems.
+type validator struct {
+	errs ValidationErrors
+}
+
+func (v *validator) report(path string, format string, args ...interface{}) {
+	v.errs = append(v.errs, &ValidationError{
+		Path:   path,
+		Reason: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+func (v *validator) validateKey(path string, key string) {
+	if len(key) == 0 {
+		v.report(path, "key is an empty string")
+		return
+	}
+	if !IsValidKey(key) {
+		v.report(path, "key %q has invalid characters", key)
+	}
+}
+
+func (v *validator) validateInteger(path string, i int64) {
+	if i > MaxInteger || i < MinInteger {
+		v.report(path, "integer %d is out of range", i)
+	}
+}
+
+func (v *validator) validateBareItem(path string, value Value) {
+	switch value := value.(type) {
+	case int8, uint8, int16, uint16, int32, uint32:
+		// always in range.
+	case int:
+		v.validateInteger(path, int64(value))
+	case uint:
+		if uint64(value) > MaxInteger {
+			v.report(path, "integer %d is out of range", value)
+		}
+	case int64:
+		v.validateInteger(path, value)
+	case uint64:
+		if value > MaxInteger {
+			v.report(path, "integer %d is out of range", value)
+		}
+
+	case float64:
+		v.validateDecimal(path, value)
+	case float32:
+		v.validateDecimal(path, float64(value))
+
+	case string:
+		if !IsValidString(value) {
+			v.report(path, "string %q has invalid characters", value)
+		}
+
+	case Token:
+		if !value.Valid() {
+			v.report(path, "token %q has invalid characters", value)
+		}
+
+	case []byte, bool:
+		// always valid.
+
+	case time.Time:
+		v.validateInteger(path, value.Unix())
+
+	case DisplayString:
+		if !utf8.ValidString(string(value)) {
+			v.report(path, "display string %q has invalid characters", value)
+		}
+
+	case InnerList:
+		v.report(path, "inner list is not allowed here")
+
+	case Raw:
+		if value.isInnerList() {
+			v.report(path, "inner list is not allowed here")
+		} else if !value.Valid() {
+			v.report(path, "raw value %q is not a valid bare item", value)
+		}
+
+	default:
+		v.report(path, "unsupported type: %T", value)
+	}
+}
+
+func (v *validator) validateDecimal(path string, f float64) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		v.report(path, "decimal %f is not a finite number", f)
+		return
+	}
+	if f > MaxDecimal || f < MinDecimal {
+		v.report(path, "decimal %f is out of range", f)
+	}
+}
+
+func (v *validator) validateParams(path string, params Parameters) {
+	for i, param := range params {
+		unique := true
+		for _, prev := range params[:i] {
+			if prev.Key == param.Key {
+				unique = false
+				break
+			}
+		}
+
+		p := paramPath(path, i, param.Key, unique)
+		v.validateKey(p, param.Key)
+		if !unique {
+			v.report(p, "duplicated parameter key %q", param.Key)
+		}
+		v.validateBareItem(p, param.Value)
+	}
+}
+
+func (v *validator) validateItem(path string, item Item) {
+	v.validateBareItem(path, item.Value)
+	v.validateParams(path, item.Parameters)
+}
+
+func (v *validator) validateItemOrInnerList(path string, item Item) {
+	switch value := item.Value.(type) {
+	case InnerList:
+		for i, item := range value {
+			v.validateItem(indexPath(path, i), item)
+		}
+	case Raw:
+		if !value.Valid() {
+			v.report(path, "raw value %q is not a valid bare item or inner list", value)
+		}
+	default:
+		v.validateBareItem(path, item.Value)
+	}
+	v.validateParams(path, item.Parameters)
+}
+
+// ValidateItem checks that item can be encoded as Structured Field Values,
+// and that the encoded value is decoded to the same value.
+// It returns all the problems as ValidationErrors, or nil if there are no problems.
+func ValidateItem(item Item) error {
+	var v validator
+	v.validateItem("", item)
+	return v.err()
+}
+
+// ValidateList checks that list can be encoded as Structured Field Values,
+// and that the encoded value is decoded to the same value.
+// It returns all the problems as ValidationErrors, or nil if there are no problems.
+func ValidateList(list List) error {
+	var v validator
+	for i, item := range list {
+		v.validateItemOrInnerList(indexPath("", i), item)
+	}
+	return v.err()
+}
+
+// ValidateDictionary checks that dict can be encoded as Structured Field Values,
+// and that the encoded value is decoded to the same value.
+// It returns all the problems as ValidationErrors, or nil if there are no problems.
+func ValidateDictionary(dict Dictionary) error {
+	var v validator
+	seenKeys := make(map[string]struct{}, len(dict))
+	for i, member := range dict {
+		_, dup := seenKeys[member.Key]
+		seenKeys[member.Key] = struct{}{}
+
+		path := memberPath("", i, member.Key, !dup)
+		v.validateKey(path, member.Key)
+		if dup {
+			v.report(path, "duplicated dictionary key %q", member.Key)
+		}
+		v.validateItemOrInnerList(path, member.Item)
+	}
+	return v.err()
+}
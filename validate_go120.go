@@ -0,0 +1,10 @@
+//go:build go1.20
+
+package sfv
+
+// Unwrap returns the problems.
+// It makes errors.Is and errors.As examine each problem.
+// It is defined only with Go 1.20 or later, where errors support multiple wrapped errors.
+func (errs ValidationErrors) Unwrap() []error {
+	return errs
+}
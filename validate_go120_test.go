@@ -0,0 +1,22 @@
+//go:build go1.20
+
+package sfv
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidationErrors_Unwrap(t *testing.T) {
+	err := ValidateList(List{
+		{Value: Token("0")},
+		{Value: "ok", Parameters: Parameters{{Key: "a", Value: "\n"}}},
+	})
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("want *ValidationError, got %T", err)
+	}
+	if verr.Path != "[0]" {
+		t.Errorf("want path %q, got %q", "[0]", verr.Path)
+	}
+}
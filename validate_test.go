@@ -0,0 +1,189 @@
+package sfv
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	for _, tt := range allTestCases(t) {
+		if tt.MustFail {
+			continue
+		}
+		tt := tt
+		t.Run(tt.Name, func(t *testing.T) {
+			// all decoded values are valid.
+			switch tt.HeaderType {
+			case headerTypeItem:
+				item, err := DecodeItem(tt.Raw)
+				if err != nil {
+					return
+				}
+				if err := ValidateItem(item); err != nil {
+					t.Error(err)
+				}
+			case headerTypeList:
+				list, err := DecodeList(tt.Raw)
+				if err != nil {
+					return
+				}
+				if err := ValidateList(list); err != nil {
+					t.Error(err)
+				}
+			case headerTypeDictionary:
+				dict, err := DecodeDictionary(tt.Raw)
+				if err != nil {
+					return
+				}
+				if err := ValidateDictionary(dict); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateItem(t *testing.T) {
+	err := ValidateItem(Item{
+		Value: int64(MaxInteger + 1),
+		Parameters: Parameters{
+			{Key: "a", Value: math.NaN()},
+			{Key: "A", Value: Token("0")},
+			{Key: "a", Value: "\n"},
+			{Key: "b", Value: InnerList{}},
+			{Key: "c", Value: make(chan int)},
+		},
+	})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("want ValidationErrors, got %T", err)
+	}
+	if len(errs) != 8 {
+		t.Errorf("want 8 errors, got %d: %v", len(errs), errs)
+	}
+
+	if err := ValidateItem(Item{Value: uint64(MaxInteger)}); err != nil {
+		t.Error(err)
+	}
+	if err := ValidateItem(Item{Value: uint64(MaxInteger + 1)}); err == nil {
+		t.Error("want error, but not")
+	}
+	if err := ValidateItem(Item{Value: DisplayString("\x80")}); err == nil {
+		t.Error("want error, but not")
+	}
+}
+
+func TestValidateList(t *testing.T) {
+	err := ValidateList(List{
+		{
+			Value: InnerList{
+				{Value: InnerList{}},
+				{Value: Token("a"), Parameters: Parameters{{Key: "x", Value: 1}, {Key: "x", Value: 2}}},
+			},
+			Parameters: Parameters{{Key: "", Value: true}},
+		},
+		{Value: 1.5},
+	})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("want ValidationErrors, got %T", err)
+	}
+	if len(errs) != 3 {
+		t.Errorf("want 3 errors, got %d: %v", len(errs), errs)
+	}
+}
+
+func TestValidateDictionary(t *testing.T) {
+	err := ValidateDictionary(Dictionary{
+		{Key: "a", Item: Item{Value: int64(1)}},
+		{Key: "b", Item: Item{Value: InnerList{{Value: int64(2)}}}},
+		{Key: "a", Item: Item{Value: int64(3)}},
+		{Key: "UPPER", Item: Item{Value: true}},
+	})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("want ValidationErrors, got %T", err)
+	}
+	if len(errs) != 2 {
+		t.Errorf("want 2 errors, got %d: %v", len(errs), errs)
+	}
+	want := "sfv: [2]: duplicated dictionary key \"a\"\nsfv: [3]: key \"UPPER\" has invalid characters"
+	if err.Error() != want {
+		t.Errorf("want %q, got %q", want, err.Error())
+	}
+
+	if err := ValidateDictionary(nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValidate_paths(t *testing.T) {
+	err := ValidateDictionary(Dictionary{
+		{
+			Key: "a",
+			Item: Item{
+				Value: InnerList{
+					{Value: Token("ok")},
+					{Value: Token("ok"), Parameters: Parameters{{Key: "b", Value: int64(MaxInteger + 1)}}},
+				},
+				Parameters: Parameters{
+					{Key: "c", Value: "ok"},
+					{Key: "C", Value: "ok"},
+					{Key: "c", Value: "\n"},
+				},
+			},
+		},
+		{
+			Key:  "d",
+			Item: Item{Value: math.Inf(1)},
+		},
+	})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("want ValidationErrors, got %T", err)
+	}
+	var got []ValidationError
+	for _, err := range errs {
+		got = append(got, *err.(*ValidationError))
+	}
+	want := []ValidationError{
+		{Path: "a[1];b", Reason: "integer 1000000000000000 is out of range"},
+		{Path: "a;[1]", Reason: `key "C" has invalid characters`},
+		{Path: "a;[2]", Reason: `duplicated parameter key "c"`},
+		{Path: "a;[2]", Reason: `string "\n" has invalid characters`},
+		{Path: "d", Reason: "decimal +Inf is not a finite number"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+	if got, want := errs[0].Error(), "sfv: a[1];b: integer 1000000000000000 is out of range"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	err = ValidateList(List{{Value: Token("ok")}, {Value: make(chan int)}})
+	if got, want := err.Error(), "sfv: [1]: unsupported type: chan int"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	err = ValidateItem(Item{Value: Token("")})
+	if got, want := err.Error(), `sfv: token "" has invalid characters`; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestValidate_raw(t *testing.T) {
+	if err := ValidateList(List{{Value: Raw("(1 2)")}, {Value: Raw("1")}}); err != nil {
+		t.Error(err)
+	}
+	if err := ValidateList(List{{Value: Raw("(1 2")}}); err == nil {
+		t.Error("want error, but not")
+	}
+	if err := ValidateItem(Item{Value: Raw("(1 2)")}); err == nil {
+		t.Error("want error, but not")
+	}
+	if err := ValidateItem(Item{Value: int64(1), Parameters: Parameters{{Key: "a", Value: Raw("a b")}}}); err == nil {
+		t.Error("want error, but not")
+	}
+}
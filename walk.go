@@ -0,0 +1,325 @@
+package sfv
+
+import (
+	"errors"
+	"fmt"
+)
+
+// NodeKind is the kind of a Node.
+type NodeKind int
+
+const (
+	// NodeList is the top-level list.
+	NodeList NodeKind = iota + 1
+
+	// NodeDictionary is the top-level dictionary.
+	NodeDictionary
+
+	// NodeItem is an item whose value is a bare item.
+	NodeItem
+
+	// NodeInnerList is an item whose value is an inner list.
+	NodeInnerList
+
+	// NodeParameter is a parameter of an item or an inner list.
+	NodeParameter
+)
+
+func (k NodeKind) String() string {
+	switch k {
+	case NodeList:
+		return "List"
+	case NodeDictionary:
+		return "Dictionary"
+	case NodeItem:
+		return "Item"
+	case NodeInnerList:
+		return "InnerList"
+	case NodeParameter:
+		return "Parameter"
+	}
+	return fmt.Sprintf("NodeKind(%d)", int(k))
+}
+
+// Node is a node of the tree of a structured field value.
+type Node struct {
+	Kind NodeKind
+
+	// Path is the location of the node.
+	// The syntax is same as ValidationError.Path.
+	Path string
+
+	// Key is the key of the dictionary member or the parameter.
+	Key string
+
+	// Index is the index of the node in its parent, or -1 for the top-level node.
+	Index int
+
+	// Value is the value of the node.
+	// It is List for NodeList, Dictionary for NodeDictionary, InnerList for NodeInnerList,
+	// and a bare item for NodeItem and NodeParameter.
+	Value Value
+
+	// Parameters is the parameters of NodeItem and NodeInnerList.
+	Parameters Parameters
+}
+
+// SkipChildren is used as a return value from WalkFunc and TransformFunc
+// to indicate that the children of the node are to be skipped.
+// It is not returned as an error by any function.
+var SkipChildren = errors.New("sfv: skip children")
+
+// SkipAll is used as a return value from WalkFunc and TransformFunc
+// to indicate that all remaining nodes are to be skipped.
+// It is not returned as an error by any function.
+var SkipAll = errors.New("sfv: skip all")
+
+// WalkFunc is the type of the function called by Walk to visit each node.
+// If the function returns SkipChildren or SkipAll, Walk skips the children or all the remaining nodes.
+// If the function returns any other non-nil error, Walk stops and returns the error.
+type WalkFunc func(node Node) error
+
+// TransformFunc is the type of the function called by Transform to visit each node.
+// It returns the new value of the node, or node.Value to keep it.
+// The new value of NodeList and NodeDictionary must be List and Dictionary.
+// The errors are handled in the same way as WalkFunc.
+type TransformFunc func(node Node) (Value, error)
+
+// Walk walks the tree of v in depth-first order, calling fn for each node.
+// v must be an Item, a List or a Dictionary.
+// The parent is visited before its children, and the value of an item is visited before its parameters.
+func Walk(v interface{}, fn WalkFunc) error {
+	w := &walker{
+		fn: func(node Node) (Value, error) {
+			return node.Value, fn(node)
+		},
+	}
+	_, err := w.walk(v)
+	return err
+}
+
+// Transform walks the tree of v in the same order as Walk,
+// and returns a copy of v whose values are replaced with the results of fn.
+// v must be an Item, a List or a Dictionary, and the result has the same type as v.
+// The children of a node are visited after it is replaced.
+// v is not modified.
+func Transform(v interface{}, fn TransformFunc) (interface{}, error) {
+	w := &walker{
+		fn:        fn,
+		transform: true,
+	}
+	return w.walk(v)
+}
+
+type walker struct {
+	fn        TransformFunc
+	transform bool
+	stopped   bool
+}
+
+// visit calls fn for the node, and reports whether its children should be visited.
+func (w *walker) visit(node Node) (Value, bool, error) {
+	v, err := w.fn(node)
+	if err == SkipChildren {
+		return v, false, nil
+	}
+	if err == SkipAll {
+		w.stopped = true
+		return v, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return v, !w.stopped, nil
+}
+
+func (w *walker) walk(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case Item:
+		return w.walkItem(Node{Index: -1}, v)
+	case List:
+		return w.walkList(v)
+	case Dictionary:
+		return w.walkDictionary(v)
+	}
+	return nil, fmt.Errorf("sfv: unsupported type: %T", v)
+}
+
+func (w *walker) walkList(list List) (List, error) {
+	v, descend, err := w.visit(Node{
+		Kind:  NodeList,
+		Index: -1,
+		Value: list,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if w.transform {
+		var ok bool
+		if list, ok = v.(List); !ok {
+			return nil, fmt.Errorf("sfv: cannot replace the list with %T", v)
+		}
+	}
+	if !descend {
+		return list, nil
+	}
+
+	if w.transform {
+		list = append(List(nil), list...)
+	}
+	for i, item := range list {
+		item, err := w.walkItem(Node{Path: indexPath("", i), Index: i}, item)
+		if err != nil {
+			return nil, err
+		}
+		if w.transform {
+			list[i] = item
+		}
+		if w.stopped {
+			break
+		}
+	}
+	return list, nil
+}
+
+func (w *walker) walkDictionary(dict Dictionary) (Dictionary, error) {
+	v, descend, err := w.visit(Node{
+		Kind:  NodeDictionary,
+		Index: -1,
+		Value: dict,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if w.transform {
+		var ok bool
+		if dict, ok = v.(Dictionary); !ok {
+			return nil, fmt.Errorf("sfv: cannot replace the dictionary with %T", v)
+		}
+	}
+	if !descend {
+		return dict, nil
+	}
+
+	if w.transform {
+		dict = append(Dictionary(nil), dict...)
+	}
+	seenKeys := make(map[string]struct{}, len(dict))
+	for i, member := range dict {
+		_, dup := seenKeys[member.Key]
+		seenKeys[member.Key] = struct{}{}
+
+		item, err := w.walkItem(Node{
+			Path:  memberPath("", i, member.Key, !dup),
+			Key:   member.Key,
+			Index: i,
+		}, member.Item)
+		if err != nil {
+			return nil, err
+		}
+		if w.transform {
+			dict[i].Item = item
+		}
+		if w.stopped {
+			break
+		}
+	}
+	return dict, nil
+}
+
+// itemNode returns the node of the item or the inner list.
+// node has the location of the item.
+func itemNode(node Node, item Item) Node {
+	node.Kind = NodeItem
+	if _, ok := item.Value.(InnerList); ok {
+		node.Kind = NodeInnerList
+	}
+	node.Value = item.Value
+	node.Parameters = item.Parameters
+	return node
+}
+
+// walkItem visits the item or the inner list.
+// node has the location of the item.
+func (w *walker) walkItem(node Node, item Item) (Item, error) {
+	node = itemNode(node, item)
+	v, descend, err := w.visit(node)
+	if err != nil {
+		return Item{}, err
+	}
+	if w.transform {
+		item.Value = v
+	}
+	if !descend {
+		return item, nil
+	}
+
+	if list, ok := item.Value.(InnerList); ok && len(list) > 0 {
+		if w.transform {
+			list = append(InnerList(nil), list...)
+		}
+		for i, inner := range list {
+			inner, err := w.walkItem(Node{Path: indexPath(node.Path, i), Index: i}, inner)
+			if err != nil {
+				return Item{}, err
+			}
+			if w.transform {
+				list[i] = inner
+			}
+			if w.stopped {
+				break
+			}
+		}
+		if w.transform {
+			item.Value = list
+		}
+	}
+	if w.stopped {
+		return item, nil
+	}
+
+	params, err := w.walkParams(node.Path, item.Parameters)
+	if err != nil {
+		return Item{}, err
+	}
+	if w.transform {
+		item.Parameters = params
+	}
+	return item, nil
+}
+
+func (w *walker) walkParams(path string, params Parameters) (Parameters, error) {
+	if len(params) == 0 {
+		return params, nil
+	}
+	if w.transform {
+		params = append(Parameters(nil), params...)
+	}
+	for i, param := range params {
+		unique := true
+		for _, prev := range params[:i] {
+			if prev.Key == param.Key {
+				unique = false
+				break
+			}
+		}
+
+		v, _, err := w.visit(Node{
+			Kind:  NodeParameter,
+			Path:  paramPath(path, i, param.Key, unique),
+			Key:   param.Key,
+			Index: i,
+			Value: param.Value,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if w.transform {
+			params[i].Value = v
+		}
+		if w.stopped {
+			break
+		}
+	}
+	return params, nil
+}
@@ -0,0 +1,184 @@
+package sfv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func mustDecodeDictionary(t *testing.T, field string) Dictionary {
+	t.Helper()
+	dict, err := DecodeDictionary([]string{field})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dict
+}
+
+func TestWalk(t *testing.T) {
+	dict := mustDecodeDictionary(t, `a=(1 2;x);y, b;z="foo"`)
+
+	var got []string
+	err := Walk(dict, func(node Node) error {
+		got = append(got, node.Kind.String()+" "+node.Path)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Dictionary ",
+		"InnerList a",
+		"Item a[0]",
+		"Item a[1]",
+		"Parameter a[1];x",
+		"Parameter a;y",
+		"Item b",
+		"Parameter b;z",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestWalk_duplicatedKeys(t *testing.T) {
+	// the decoder never produces duplicated keys, but users can.
+	list := List{
+		{
+			Value: int64(1),
+			Parameters: Parameters{
+				{Key: "a", Value: int64(1)},
+				{Key: "a", Value: int64(2)},
+				{Key: "INVALID", Value: int64(3)},
+			},
+		},
+	}
+
+	var got []string
+	err := Walk(list, func(node Node) error {
+		got = append(got, node.Path)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"", "[0]", "[0];a", "[0];[1]", "[0];[2]"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestWalk_skip(t *testing.T) {
+	dict := mustDecodeDictionary(t, `a=(1 2;x);y, b;z="foo", c`)
+
+	var got []string
+	err := Walk(dict, func(node Node) error {
+		got = append(got, node.Path)
+		switch node.Path {
+		case "a":
+			return SkipChildren
+		case "b;z":
+			return SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"", "a", "b", "b;z"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestWalk_error(t *testing.T) {
+	errStop := errors.New("stop")
+	var got []string
+	err := Walk(Item{Value: int64(1), Parameters: Parameters{{Key: "a", Value: true}, {Key: "b", Value: true}}}, func(node Node) error {
+		got = append(got, node.Path)
+		if node.Kind == NodeParameter {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop {
+		t.Errorf("want %v, got %v", errStop, err)
+	}
+	want := []string{"", ";a"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	if err := Walk("foo", func(node Node) error { return nil }); err == nil {
+		t.Error("want error, but not")
+	}
+}
+
+func TestTransform(t *testing.T) {
+	dict := mustDecodeDictionary(t, `a=("secret" 2;token="secret");y, b;token="secret"`)
+	orig := mustDecodeDictionary(t, `a=("secret" 2;token="secret");y, b;token="secret"`)
+
+	// redact the tokens.
+	got, err := Transform(dict, func(node Node) (Value, error) {
+		if node.Kind == NodeParameter && node.Key == "token" {
+			return "REDACTED", nil
+		}
+		return node.Value, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := mustDecodeDictionary(t, `a=("secret" 2;token="REDACTED");y, b;token="REDACTED"`)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %#v, got %#v", want, got)
+	}
+
+	// the original value is not modified.
+	if !reflect.DeepEqual(dict, orig) {
+		t.Errorf("want %#v, got %#v", orig, dict)
+	}
+}
+
+func TestTransform_replaceInnerList(t *testing.T) {
+	list := List{
+		{Value: InnerList{{Value: int64(1)}, {Value: int64(2)}}},
+		{Value: int64(3)},
+	}
+
+	var visited []string
+	got, err := Transform(list, func(node Node) (Value, error) {
+		visited = append(visited, node.Path)
+		if node.Kind == NodeInnerList {
+			return Token("replaced"), nil
+		}
+		if node.Path == "[1]" {
+			return int64(4), SkipAll
+		}
+		return node.Value, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := List{
+		{Value: Token("replaced")},
+		{Value: int64(4)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %#v, got %#v", want, got)
+	}
+
+	// the children of the replaced node are not visited.
+	wantVisited := []string{"", "[0]", "[1]"}
+	if !reflect.DeepEqual(visited, wantVisited) {
+		t.Errorf("want %q, got %q", wantVisited, visited)
+	}
+}
+
+func TestTransform_invalidReplacement(t *testing.T) {
+	_, err := Transform(List{}, func(node Node) (Value, error) {
+		return Dictionary{}, nil
+	})
+	if err == nil {
+		t.Error("want error, but not")
+	}
+}